@@ -0,0 +1,143 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultJREClassCount is the number of classes in a typical JRE.
+	DefaultJREClassCount = 25000
+
+	// DefaultLoadFactor is the proportion of available classes that a typical application loads.
+	DefaultLoadFactor = 0.35
+)
+
+// ClassCount returns the number of classes in a directory, JAR or WAR, including the classes in any nested JARs such
+// as BOOT-INF/lib/*.jar in Spring Boot applications and WEB-INF/lib/*.jar in web applications.  Versioned classes in
+// multi-release JARs are not counted as they replace the unversioned ones.
+func ClassCount(path string) (int, error) {
+	i, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if i.IsDir() {
+		return directoryClassCount(path)
+	}
+
+	return archiveFileClassCount(path)
+}
+
+// LoadedClassCount estimates the number of classes that will be loaded from the number of application and JRE
+// classes.
+func LoadedClassCount(applicationClassCount int, jreClassCount int, loadFactor float64) int {
+	return int(math.Ceil(float64(applicationClassCount+jreClassCount) * loadFactor))
+}
+
+func directoryClassCount(path string) (int, error) {
+	count := 0
+
+	err := filepath.Walk(path, func(p string, i os.FileInfo, err error) error {
+		if err != nil || i.IsDir() {
+			return err
+		}
+
+		if isClass(p) {
+			count++
+		} else if isArchive(p) {
+			n, err := archiveFileClassCount(p)
+			if err != nil {
+				return err
+			}
+
+			count += n
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+func archiveFileClassCount(path string) (int, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	return archiveClassCount(&r.Reader)
+}
+
+func archiveClassCount(r *zip.Reader) (int, error) {
+	count := 0
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "META-INF/versions/") {
+			continue
+		}
+
+		if isClass(f.Name) {
+			count++
+		} else if isArchive(f.Name) {
+			n, err := nestedArchiveClassCount(f)
+			if err != nil {
+				return 0, err
+			}
+
+			count += n
+		}
+	}
+
+	return count, nil
+}
+
+func nestedArchiveClassCount(f *zip.File) (int, error) {
+	in, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	b, err := ioutil.ReadAll(in)
+	if err != nil {
+		return 0, err
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return 0, err
+	}
+
+	return archiveClassCount(r)
+}
+
+func isArchive(path string) bool {
+	return strings.HasSuffix(path, ".jar")
+}
+
+func isClass(path string) bool {
+	return strings.HasSuffix(path, ".class")
+}
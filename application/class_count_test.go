@@ -0,0 +1,139 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/application"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestClassCount(t *testing.T) {
+	spec.Run(t, "ClassCount", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var path string
+
+		archive := func(entries map[string][]byte) []byte {
+			b := &bytes.Buffer{}
+			w := zip.NewWriter(b)
+
+			for name, content := range entries {
+				f, err := w.Create(name)
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = f.Write(content)
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			g.Expect(w.Close()).To(Succeed())
+			return b.Bytes()
+		}
+
+		write := func(name string, content []byte) string {
+			f := filepath.Join(path, name)
+			g.Expect(os.MkdirAll(filepath.Dir(f), 0755)).To(Succeed())
+			g.Expect(ioutil.WriteFile(f, content, 0644)).To(Succeed())
+			return f
+		}
+
+		it.Before(func() {
+			var err error
+			path, err = ioutil.TempDir("", "application")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("counts classes in directory", func() {
+			write("com/example/A.class", nil)
+			write("com/example/B.class", nil)
+			write("application.properties", nil)
+			write("lib/library.jar", archive(map[string][]byte{"com/library/C.class": nil}))
+
+			g.Expect(application.ClassCount(path)).To(Equal(3))
+		})
+
+		it("counts classes in JAR", func() {
+			f := write("application.jar", archive(map[string][]byte{
+				"META-INF/MANIFEST.MF":                     nil,
+				"META-INF/versions/11/com/example/A.class": nil,
+				"com/example/A.class":                      nil,
+				"com/example/B.class":                      nil,
+			}))
+
+			g.Expect(application.ClassCount(f)).To(Equal(2))
+		})
+
+		it("counts classes in Spring Boot JAR", func() {
+			f := write("application.jar", archive(map[string][]byte{
+				"org/springframework/boot/loader/JarLauncher.class": nil,
+				"BOOT-INF/classes/com/example/A.class":              nil,
+				"BOOT-INF/lib/library-1.jar":                        archive(map[string][]byte{"com/library/B.class": nil, "com/library/C.class": nil}),
+				"BOOT-INF/lib/library-2.jar":                        archive(map[string][]byte{"com/library/D.class": nil}),
+			}))
+
+			g.Expect(application.ClassCount(f)).To(Equal(5))
+		})
+
+		it("counts classes in WAR", func() {
+			f := write("application.war", archive(map[string][]byte{
+				"index.html":                          nil,
+				"WEB-INF/classes/com/example/A.class": nil,
+				"WEB-INF/lib/library.jar":             archive(map[string][]byte{"com/library/B.class": nil}),
+			}))
+
+			g.Expect(application.ClassCount(f)).To(Equal(2))
+		})
+
+		it("returns error if path does not exist", func() {
+			_, err := application.ClassCount(filepath.Join(path, "does-not-exist"))
+			g.Expect(err).To(HaveOccurred())
+		})
+
+		it("returns error if file is not an archive", func() {
+			f := write("application.jar", []byte("not an archive"))
+
+			_, err := application.ClassCount(f)
+			g.Expect(err).To(HaveOccurred())
+		})
+	})
+}
+
+func TestLoadedClassCount(t *testing.T) {
+	spec.Run(t, "LoadedClassCount", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("applies load factor to application and JRE classes", func() {
+			g.Expect(application.LoadedClassCount(5000, 25000, 0.35)).To(Equal(10500))
+		})
+
+		it("rounds up", func() {
+			g.Expect(application.LoadedClassCount(1, 0, 0.5)).To(Equal(1))
+		})
+	})
+}
@@ -0,0 +1,326 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultBaselineThreadCount is the number of threads, other than request processing threads, that a typical
+	// application starts, e.g. for schedulers, connection pools and client libraries.  The JVM's own threads are not
+	// included as they are accounted for by the calculator.
+	DefaultBaselineThreadCount = 25
+
+	// DefaultRequestThreadCount is the maximum number of request processing threads of Tomcat and Jetty if it is not
+	// configured.
+	DefaultRequestThreadCount = 200
+)
+
+// requestThreadProperties are the properties that configure the maximum number of request processing threads, in
+// order of precedence.
+var requestThreadProperties = []string{
+	"server.tomcat.threads.max",
+	"server.tomcat.max-threads",
+	"server.jetty.threads.max",
+	"server.jetty.max-threads",
+	"server.undertow.threads.worker",
+	"server.undertow.worker-threads",
+	"jetty.threadPool.maxThreads",
+}
+
+// placeholderRE matches a Spring ${NAME:default} or Tomcat ${NAME:-default} placeholder, with an optional default.
+var placeholderRE = regexp.MustCompile(`^\$\{[^:}]*(?::-?([^}]*))?}$`)
+
+// RequestThreads is the maximum number of request processing threads of an application.
+type RequestThreads struct {
+	Count int
+
+	// Source describes where Count was configured, e.g. "server.tomcat.threads.max in application.properties".  It is
+	// empty if DefaultRequestThreadCount is used.
+	Source string
+}
+
+// RequestThreadCount returns the maximum number of request processing threads configured in a directory, JAR or WAR.
+// It is read from the first of Spring Boot's application.properties or application.yml, Tomcat's server.xml, or
+// Jetty's start.ini or start.d/*.ini that configures it.  DefaultRequestThreadCount is returned if none does.
+func RequestThreadCount(path string) (RequestThreads, error) {
+	i, err := os.Stat(path)
+	if err != nil {
+		return RequestThreads{}, err
+	}
+
+	var t *RequestThreads
+	if i.IsDir() {
+		t, err = directoryRequestThreads(path)
+	} else {
+		t, err = archiveFileRequestThreads(path)
+	}
+
+	if err != nil {
+		return RequestThreads{}, err
+	}
+
+	if t == nil {
+		return RequestThreads{Count: DefaultRequestThreadCount}, nil
+	}
+
+	return *t, nil
+}
+
+func directoryRequestThreads(root string) (*RequestThreads, error) {
+	var t *RequestThreads
+
+	err := filepath.Walk(root, func(p string, i os.FileInfo, err error) error {
+		if err != nil || i.IsDir() || t != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		parse := requestThreadParser(filepath.ToSlash(name))
+		if parse == nil {
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		t, err = requestThreads(filepath.ToSlash(name), b, parse)
+		return err
+	})
+
+	return t, err
+}
+
+func archiveFileRequestThreads(path string) (*RequestThreads, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		parse := requestThreadParser(f.Name)
+		if f.FileInfo().IsDir() || parse == nil {
+			continue
+		}
+
+		b, err := readArchiveFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := requestThreads(f.Name, b, parse)
+		if err != nil || t != nil {
+			return t, err
+		}
+	}
+
+	return nil, nil
+}
+
+func readArchiveFile(f *zip.File) ([]byte, error) {
+	in, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	return ioutil.ReadAll(in)
+}
+
+type requestThreadParserFunc func(b []byte) (key string, count int, err error)
+
+func requestThreadParser(name string) requestThreadParserFunc {
+	base := path.Base(name)
+
+	switch {
+	case base == "application.properties", base == "start.ini",
+		path.Base(path.Dir(name)) == "start.d" && strings.HasSuffix(base, ".ini"):
+		return propertiesRequestThreads
+	case base == "application.yml", base == "application.yaml":
+		return yamlRequestThreads
+	case base == "server.xml":
+		return serverXMLRequestThreads
+	default:
+		return nil
+	}
+}
+
+func requestThreads(name string, b []byte, parse requestThreadParserFunc) (*RequestThreads, error) {
+	key, count, err := parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", name, err)
+	}
+
+	if key == "" {
+		return nil, nil
+	}
+
+	return &RequestThreads{Count: count, Source: fmt.Sprintf("%s in %s", key, name)}, nil
+}
+
+func propertiesRequestThreads(b []byte) (string, int, error) {
+	properties := make(map[string]string)
+
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			continue
+		}
+
+		properties[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+
+	return lookupRequestThreads(properties)
+}
+
+func yamlRequestThreads(b []byte) (string, int, error) {
+	properties := make(map[string]string)
+
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var document interface{}
+		if err := d.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", 0, err
+		}
+
+		flatten("", document, properties)
+	}
+
+	return lookupRequestThreads(properties)
+}
+
+// flatten adds every scalar of a YAML document to properties with its dotted key.  Earlier documents take precedence.
+func flatten(prefix string, value interface{}, properties map[string]string) {
+	m, ok := value.(map[interface{}]interface{})
+	if !ok {
+		if _, ok := properties[prefix]; !ok && prefix != "" {
+			properties[prefix] = fmt.Sprint(value)
+		}
+
+		return
+	}
+
+	for k, v := range m {
+		key := fmt.Sprint(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		flatten(key, v, properties)
+	}
+}
+
+// lookupRequestThreads returns the first property that configures the maximum number of request processing threads
+// with a value that can be resolved.
+func lookupRequestThreads(properties map[string]string) (string, int, error) {
+	for _, key := range requestThreadProperties {
+		if n, ok := resolve(properties[key]); ok {
+			return key, n, nil
+		}
+	}
+
+	return "", 0, nil
+}
+
+// resolve returns an integer value, or the default of a placeholder, and whether it could be resolved.  Placeholders
+// without a default and other values that are not integers are unresolved, as they are only known when the
+// application is running.
+func resolve(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+
+	if g := placeholderRE.FindStringSubmatch(value); g != nil {
+		value = strings.TrimSpace(g[1])
+	}
+
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+// serverXMLRequestThreads returns the sum of the maximum threads of every Tomcat executor and every connector that
+// does not use an executor.
+func serverXMLRequestThreads(b []byte) (string, int, error) {
+	var (
+		count int
+		found bool
+	)
+
+	d := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", 0, err
+		}
+
+		e, ok := t.(xml.StartElement)
+		if !ok || (e.Name.Local != "Connector" && e.Name.Local != "Executor") {
+			continue
+		}
+
+		attributes := make(map[string]string)
+		for _, a := range e.Attr {
+			attributes[a.Name.Local] = a.Value
+		}
+
+		if _, ok := attributes["executor"]; ok {
+			continue
+		}
+
+		n, ok := resolve(attributes["maxThreads"])
+		if !ok {
+			n = DefaultRequestThreadCount
+		}
+
+		count += n
+		found = true
+	}
+
+	if !found {
+		return "", 0, nil
+	}
+
+	return "maxThreads", count, nil
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/application"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestRequestThreadCount(t *testing.T) {
+	spec.Run(t, "RequestThreadCount", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var path string
+
+		write := func(name string, content string) string {
+			f := filepath.Join(path, name)
+			g.Expect(os.MkdirAll(filepath.Dir(f), 0755)).To(Succeed())
+			g.Expect(ioutil.WriteFile(f, []byte(content), 0644)).To(Succeed())
+			return f
+		}
+
+		it.Before(func() {
+			var err error
+			path, err = ioutil.TempDir("", "application")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("reads application.properties in Spring Boot JAR", func() {
+			b := &bytes.Buffer{}
+			w := zip.NewWriter(b)
+			f, err := w.Create("BOOT-INF/classes/application.properties")
+			g.Expect(err).NotTo(HaveOccurred())
+			_, err = f.Write([]byte("# comment\nserver.port=8080\nserver.tomcat.threads.max = 50\n"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(w.Close()).To(Succeed())
+
+			a := write("application.jar", b.String())
+
+			g.Expect(application.RequestThreadCount(a)).To(Equal(application.RequestThreads{
+				Count:  50,
+				Source: "server.tomcat.threads.max in BOOT-INF/classes/application.properties",
+			}))
+		})
+
+		it("reads application.yml", func() {
+			write("BOOT-INF/classes/application.yml", `server:
+  undertow:
+    threads:
+      worker: 64
+---
+server:
+  undertow:
+    threads:
+      worker: 128
+`)
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count:  64,
+				Source: "server.undertow.threads.worker in BOOT-INF/classes/application.yml",
+			}))
+		})
+
+		it("reads server.xml", func() {
+			write("conf/server.xml", `<Server>
+  <Service name="Catalina">
+    <Executor name="tomcatThreadPool" maxThreads="150"/>
+    <Connector port="8080" executor="tomcatThreadPool"/>
+    <Connector port="8009" protocol="AJP/1.3"/>
+  </Service>
+</Server>
+`)
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count:  350,
+				Source: "maxThreads in conf/server.xml",
+			}))
+		})
+
+		it("reads Jetty start.d", func() {
+			write("start.d/threadpool.ini", "--module=threadpool\njetty.threadPool.maxThreads=300\n")
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count:  300,
+				Source: "jetty.threadPool.maxThreads in start.d/threadpool.ini",
+			}))
+		})
+
+		it("returns default if not configured", func() {
+			write("BOOT-INF/classes/application.properties", "server.port=8080\n")
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count: application.DefaultRequestThreadCount,
+			}))
+		})
+
+		it("uses default of placeholder", func() {
+			write("BOOT-INF/classes/application.properties", "server.tomcat.threads.max=${MAX_THREADS:150}\n")
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count:  150,
+				Source: "server.tomcat.threads.max in BOOT-INF/classes/application.properties",
+			}))
+		})
+
+		it("uses default of placeholder in server.xml", func() {
+			write("conf/server.xml", `<Server><Service><Connector port="8080" maxThreads="${tomcat.threads:-75}"/></Service></Server>`)
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count:  75,
+				Source: "maxThreads in conf/server.xml",
+			}))
+		})
+
+		it("falls through unresolved values", func() {
+			write("BOOT-INF/classes/application.yml", "server:\n  tomcat:\n    threads:\n      max: ${MAX_THREADS}\n    max-threads: 80\n")
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count:  80,
+				Source: "server.tomcat.max-threads in BOOT-INF/classes/application.yml",
+			}))
+		})
+
+		it("returns default if value is not an integer", func() {
+			write("application.properties", "server.tomcat.threads.max=many\n")
+
+			g.Expect(application.RequestThreadCount(path)).To(Equal(application.RequestThreads{
+				Count: application.DefaultRequestThreadCount,
+			}))
+		})
+
+		it("returns error if path does not exist", func() {
+			_, err := application.RequestThreadCount(filepath.Join(path, "does-not-exist"))
+			g.Expect(err).To(HaveOccurred())
+		})
+	})
+}
@@ -18,96 +18,452 @@ package calculator
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
 )
 
+const (
+	// CompilerArenaPerThread is the native memory used by the arenas of each JIT compiler thread.
+	CompilerArenaPerThread = memory.Size(16 * memory.Mibi)
+
+	// InternalThreadStack is the stack size of the JVM's internal threads, which is not configured by -Xss.
+	InternalThreadStack = memory.Size(memory.Mibi)
+
+	// MetaspaceBase is the metaspace required regardless of the number of loaded classes.
+	MetaspaceBase = memory.Size(14000000)
+
+	// MetaspacePerClass is the metaspace required for each loaded class.
+	MetaspacePerClass = memory.Size(5800)
+)
+
+// ergonomicMaxHeap is the JVM's default max heap on 64-bit platforms, which it uses in preference to
+// -XX:MaxRAMPercentage unless -XX:MinRAMPercentage of physical memory is smaller.
+const ergonomicMaxHeap = memory.Size(96 * memory.Mibi * 13 / 10 / 8 * 8)
+
+// gcOverheads are estimates of the native memory used by the data structures of each garbage collector (e.g. card
+// tables, remembered sets and marking bitmaps) as a fraction of the heap.
+var gcOverheads = map[memory.GarbageCollector]float64{
+	memory.GarbageCollectorG1:         0.10,
+	memory.GarbageCollectorParallel:   0.03,
+	memory.GarbageCollectorSerial:     0.01,
+	memory.GarbageCollectorShenandoah: 0.05,
+	memory.GarbageCollectorZ:          0.05,
+}
+
+// GCOverhead returns the native memory used by a garbage collector as a fraction of the heap.
+func GCOverhead(g memory.GarbageCollector) float64 {
+	return gcOverheads[g]
+}
+
 type Calculator struct {
-	HeadRoom         *flags.HeadRoom
-	JvmOptions       *flags.JVMOptions
-	LoadedClassCount *flags.LoadedClassCount
-	ThreadCount      *flags.ThreadCount
-	TotalMemory      *flags.TotalMemory
+	CPUCount              *flags.CPUCount
+	HeadRoom              *flags.HeadRoom
+	HeadRoomSize          *flags.HeadRoomSize
+	HeapMode              *flags.HeapMode
+	InitialHeapPercentage *flags.InitialHeapPercentage
+	JvmOptions            *flags.JVMOptions
+	JvmVendor             *flags.JVMVendor
+	JvmVersion            *flags.JVMVersion
+	LoadedClassCount      *flags.LoadedClassCount
+	MinCodeCache          *flags.MinCodeCache
+	MinDirectMemory       *flags.MinDirectMemory
+	MinHeap               *flags.MinHeap
+	MinStack              *flags.MinStack
+	Release               *jvm.Release
+	ThreadCount           *flags.ThreadCount
+	TotalMemory           *flags.TotalMemory
 }
 
+// Calculate returns the JVM options for every region that was not specified by the user.
+//
+// Deprecated: use CalculateResult, which exposes every region without requiring the options to be type-switched.
 func (c Calculator) Calculate() ([]fmt.Stringer, error) {
-	var options []fmt.Stringer
+	r, err := c.CalculateResult()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Flags(), nil
+}
+
+// CalculateResult calculates the size of every memory region, using the values specified in JvmOptions as fixed.
+func (c Calculator) CalculateResult() (Result, error) {
+	r := Result{
+		HeadRoom:    c.headRoom(),
+		JVMVendor:   c.vendor(),
+		JVMVersion:  c.version(),
+		Sources:     make(map[Region]Source),
+		ThreadCount: int(*c.ThreadCount),
+		TotalMemory: memory.Size(*c.TotalMemory),
+	}
+
+	if r.HeadRoom > r.TotalMemory {
+		return Result{}, fmt.Errorf("head room %s is greater than total memory %s", r.HeadRoom, r.TotalMemory)
+	}
 
 	j := c.JvmOptions
 	if j == nil {
 		j = &flags.JVMOptions{}
 	}
 
-	headRoom := c.headRoom()
+	if j.MaxDirectMemory != nil {
+		r.MaxDirectMemory, r.Sources[RegionMaxDirectMemory] = *j.MaxDirectMemory, SourceJVMOptions
+	} else {
+		r.MaxDirectMemory, r.Sources[RegionMaxDirectMemory] = memory.DefaultMaxDirectMemory, SourceDefault
+	}
+
+	d := jvm.DefaultsFor(r.JVMVersion)
 
-	directMemory := j.MaxDirectMemory
-	if directMemory == nil {
-		d := memory.DefaultMaxDirectMemory
-		directMemory = &d
-		options = append(options, *directMemory)
+	if r.JVMVendor == jvm.VendorOpenJ9 {
+		c.openJ9(j, &r)
+	} else if err := c.hotSpot(j, d, &r); err != nil {
+		return Result{}, err
 	}
 
-	metaspace := j.MaxMetaspace
-	if metaspace == nil {
-		m := c.metaspace()
-		metaspace = &m
-		options = append(options, *metaspace)
+	if j.Stack != nil {
+		r.Stack, r.Sources[RegionStack] = *j.Stack, SourceJVMOptions
+	} else {
+		r.Stack, r.Sources[RegionStack] = d.Stack, SourceDefault
 	}
 
-	reservedCodeCache := j.ReservedCodeCache
-	if reservedCodeCache == nil {
-		r := memory.DefaultReservedCodeCache
-		reservedCodeCache = &r
-		options = append(options, *reservedCodeCache)
+	if c.CPUCount != nil && *c.CPUCount > 0 {
+		cpus := int(*c.CPUCount)
+
+		r.CPUCount = cpus
+		r.InternalThreadCount, r.Sources[RegionInternalStack] = jvm.InternalThreadCount(cpus), SourceCalculated
+		r.CompilerArena, r.Sources[RegionCompilerArena] = CompilerArenaPerThread*memory.Size(jvm.CompilerThreads(cpus)), SourceCalculated
 	}
 
-	stack := j.Stack
-	if stack == nil {
-		s := memory.DefaultStack
-		stack = &s
-		options = append(options, *stack)
+	gc := c.gcOverhead(r.JVMVendor, j)
+	ramHeap, ramHeapOK := c.ramHeap(j)
+
+	var minHeap memory.Size
+	if c.MinHeap != nil && *c.MinHeap > 0 && j.MaxHeap == nil && !ramHeapOK {
+		minHeap = memory.Size(*c.MinHeap)
+		c.reduce(&r, memory.Size(math.Ceil(float64(minHeap)*(1+gc))))
 	}
 
-	overhead := c.overhead(headRoom, directMemory, metaspace, reservedCodeCache, stack)
-	available := memory.Size(*c.TotalMemory)
+	r.Overhead = c.overhead(r)
+
+	if r.Overhead > r.TotalMemory {
+		return Result{}, &InsufficientMemoryError{Available: r.TotalMemory, Required: r.Overhead, Result: r}
+	}
+
+	if j.MaxHeap != nil {
+		r.MaxHeap, r.Sources[RegionMaxHeap] = *j.MaxHeap, SourceJVMOptions
+	} else if ramHeapOK {
+		r.MaxHeap, r.Sources[RegionMaxHeap] = ramHeap, SourceJVMOptions
+	} else {
+		r.MaxHeap, r.Sources[RegionMaxHeap] = c.heap(r.Overhead, gc), SourceCalculated
+	}
+
+	if memory.Size(r.MaxHeap) < minHeap {
+		return Result{}, &InsufficientMemoryError{
+			Available: r.TotalMemory,
+			Required:  r.Overhead + memory.Size(math.Ceil(float64(minHeap)*(1+gc))),
+			Result:    r,
+		}
+	}
+
+	if gc > 0 {
+		if r.IsFixed(RegionMaxHeap) {
+			r.GCOverhead = memory.Size(float64(r.MaxHeap) * gc)
+		} else {
+			r.GCOverhead = r.TotalMemory - r.Overhead - memory.Size(r.MaxHeap)
+		}
+
+		r.Sources[RegionGCOverhead] = SourceCalculated
+		r.Overhead += r.GCOverhead
+	}
 
-	if overhead > available {
-		return nil, fmt.Errorf("required memory %s is greater than %s available for allocation: %s, %s, %s, %s x %d threads",
-			overhead, available, directMemory, metaspace, reservedCodeCache, stack, *c.ThreadCount)
+	if r.Overhead+memory.Size(r.MaxHeap) > r.TotalMemory {
+		return Result{}, &InsufficientMemoryError{Available: r.TotalMemory, Required: r.Overhead + memory.Size(r.MaxHeap), Result: r}
 	}
 
-	heap := j.MaxHeap
-	if heap == nil {
-		h := c.heap(overhead)
-		heap = &h
-		options = append(options, *heap)
+	if flag, size := c.youngGeneration(j); flag != nil && size >= memory.Size(r.MaxHeap) {
+		return Result{}, fmt.Errorf("max heap %s cannot accommodate young generation %s", r.MaxHeap, flag)
 	}
 
-	if overhead+memory.Size(*heap) > available {
-		return nil, fmt.Errorf("required memory %s is greater than %s available for allocation: %s, %s, %s, %s, %s x %d threads",
-			overhead+memory.Size(*heap), available, directMemory, heap, metaspace, reservedCodeCache, stack, *c.ThreadCount)
+	if j.InitialHeap != nil {
+		r.InitialHeap, r.Sources[RegionInitialHeap] = *j.InitialHeap, SourceJVMOptions
+	} else if j.InitialRAMPercentage != nil {
+		r.InitialHeap, r.Sources[RegionInitialHeap] = memory.InitialHeap(c.ram(j)*float64(*j.InitialRAMPercentage)/100), SourceJVMOptions
+	} else if c.InitialHeapPercentage != nil && *c.InitialHeapPercentage > 0 {
+		r.InitialHeap, r.Sources[RegionInitialHeap] = c.initialHeap(r.MaxHeap), SourceCalculated
 	}
 
-	return options, nil
+	if memory.Size(r.InitialHeap) > memory.Size(r.MaxHeap) {
+		return Result{}, fmt.Errorf("initial heap %s is greater than max heap %s", r.InitialHeap, r.MaxHeap)
+	}
+
+	if c.HeapMode != nil && *c.HeapMode == flags.HeapModePercentage {
+		if r.JVMVersion == 7 {
+			return Result{}, fmt.Errorf("--%s=%s requires Java 8 or later", flags.FlagHeapMode, flags.HeapModePercentage)
+		}
+
+		if !r.IsFixed(RegionMaxHeap) {
+			r.MaxRAMPercentage = memory.MaxRAMPercentage(percentage(memory.Size(r.MaxHeap), r.TotalMemory))
+		}
+
+		if r.IsPresent(RegionInitialHeap) && !r.IsFixed(RegionInitialHeap) {
+			r.InitialRAMPercentage = memory.InitialRAMPercentage(percentage(memory.Size(r.InitialHeap), r.TotalMemory))
+		}
+	}
+
+	return r, nil
+}
+
+// hotSpot sets the class metadata and code cache regions of HotSpot and HotSpot-based JVMs.
+func (c Calculator) hotSpot(j *flags.JVMOptions, d jvm.Defaults, r *Result) error {
+	if d.PermGen {
+		if j.MaxPermSize != nil {
+			r.MaxPermSize, r.Sources[RegionMaxPermSize] = *j.MaxPermSize, SourceJVMOptions
+		} else {
+			r.MaxPermSize, r.Sources[RegionMaxPermSize] = memory.MaxPermSize(c.metaspace()), SourceCalculated
+		}
+	} else if j.MaxMetaspace != nil {
+		r.MaxMetaspace, r.Sources[RegionMaxMetaspace] = *j.MaxMetaspace, SourceJVMOptions
+	} else {
+		r.MaxMetaspace, r.Sources[RegionMaxMetaspace] = c.metaspace(), SourceCalculated
+	}
+
+	if j.ReservedCodeCache != nil {
+		r.ReservedCodeCache, r.Sources[RegionReservedCodeCache] = *j.ReservedCodeCache, SourceJVMOptions
+	} else if j.TieredCompilation != nil {
+		r.ReservedCodeCache, r.Sources[RegionReservedCodeCache] = d.CodeCache(bool(*j.TieredCompilation)), SourceDefault
+	} else {
+		r.ReservedCodeCache, r.Sources[RegionReservedCodeCache] = d.CodeCache(d.TieredCompilation), SourceDefault
+	}
+
+	if j.CompressedClassSpace != nil && !d.PermGen {
+		if j.CompressedClassPointers == nil || *j.CompressedClassPointers {
+			if memory.Size(*j.CompressedClassSpace) > memory.Size(r.MaxMetaspace) {
+				if r.IsFixed(RegionMaxMetaspace) {
+					return fmt.Errorf("compressed class space %s is greater than max metaspace %s",
+						*j.CompressedClassSpace, r.MaxMetaspace)
+				}
+
+				// compressed class space is part of metaspace, so a calculated metaspace must contain it
+				r.MaxMetaspace = memory.MaxMetaspace(*j.CompressedClassSpace)
+			}
+		} else {
+			r.CompressedClassSpace, r.Sources[RegionCompressedClassSpace] = *j.CompressedClassSpace, SourceJVMOptions
+		}
+	}
+
+	return nil
+}
+
+// openJ9 sets the class metadata and code cache regions of OpenJ9.  OpenJ9 has no limit on class metadata, so the
+// memory it requires is only accounted for, and the shared class cache is only reserved if it is configured.
+func (c Calculator) openJ9(j *flags.JVMOptions, r *Result) {
+	r.ClassMemory, r.Sources[RegionClassMemory] = memory.Size(c.metaspace()), SourceCalculated
+
+	if j.CodeCacheTotal != nil {
+		r.CodeCacheTotal, r.Sources[RegionCodeCacheTotal] = *j.CodeCacheTotal, SourceJVMOptions
+	} else {
+		r.CodeCacheTotal, r.Sources[RegionCodeCacheTotal] = memory.DefaultCodeCacheTotal, SourceDefault
+	}
+
+	if j.SharedClassCache != nil {
+		r.SharedClassCache, r.Sources[RegionSharedClassCache] = *j.SharedClassCache, SourceJVMOptions
+	}
 }
 
 func (c Calculator) headRoom() memory.Size {
-	return memory.Size(float64(*c.TotalMemory) * (float64(*c.HeadRoom) / 100))
+	h := memory.Size(float64(*c.TotalMemory) * (float64(*c.HeadRoom) / 100))
+
+	if c.HeadRoomSize != nil && memory.Size(*c.HeadRoomSize) > h {
+		return memory.Size(*c.HeadRoomSize)
+	}
+
+	return h
+}
+
+// gcOverhead returns the fraction of the heap used by the garbage collector selected in the JVM options.  It is 0
+// if no collector is selected, as the JVM's choice depends on the resources it detects, or if the JVM is OpenJ9.
+func (c Calculator) gcOverhead(vendor jvm.Vendor, j *flags.JVMOptions) float64 {
+	if j.GarbageCollector == nil || vendor == jvm.VendorOpenJ9 {
+		return 0
+	}
+
+	return GCOverhead(*j.GarbageCollector)
+}
+
+func (c Calculator) heap(overhead memory.Size, gc float64) memory.MaxHeap {
+	available := memory.Size(*c.TotalMemory) - overhead
+
+	if gc > 0 {
+		return memory.MaxHeap(float64(available) / (1 + gc))
+	}
+
+	return memory.MaxHeap(available)
+}
+
+func (c Calculator) initialHeap(heap memory.MaxHeap) memory.InitialHeap {
+	return memory.InitialHeap(float64(heap) * (float64(*c.InitialHeapPercentage) / 100))
+}
+
+// vendor returns JvmVendor if it is specified, otherwise the vendor from Release, defaulting to HotSpot if it is not
+// known.
+func (c Calculator) vendor() jvm.Vendor {
+	if c.JvmVendor != nil && *c.JvmVendor != "" {
+		return jvm.Vendor(*c.JvmVendor)
+	}
+
+	if c.Release == nil || c.Release.Vendor == "" {
+		return jvm.VendorHotSpot
+	}
+
+	return c.Release.Vendor
+}
+
+// version returns JvmVersion if it is specified, otherwise the version from Release.  0 is unknown.
+func (c Calculator) version() int {
+	if c.JvmVersion != nil && *c.JvmVersion != 0 {
+		return int(*c.JvmVersion)
+	}
+
+	if c.Release != nil {
+		return c.Release.Version
+	}
+
+	return 0
+}
+
+// youngGeneration returns the flag and size of the largest minimum young generation configured with -Xmn or
+// -XX:NewSize.  -XX:MaxNewSize and -XX:NewRatio are not considered as the JVM limits the young generation they
+// configure to fit within the heap.
+func (c Calculator) youngGeneration(j *flags.JVMOptions) (fmt.Stringer, memory.Size) {
+	var (
+		flag fmt.Stringer
+		size memory.Size
+	)
+
+	if j.YoungGeneration != nil {
+		flag, size = *j.YoungGeneration, memory.Size(*j.YoungGeneration)
+	}
+
+	if j.NewSize != nil && memory.Size(*j.NewSize) > size {
+		flag, size = *j.NewSize, memory.Size(*j.NewSize)
+	}
+
+	return flag, size
+}
+
+// reduce shrinks the non-fixed code cache, thread stack and direct memory, in that order and no further than
+// MinCodeCache, MinStack and MinDirectMemory, until required memory is available for the heap.  Reduced regions are
+// recorded as SourceReduced.
+func (c Calculator) reduce(r *Result, required memory.Size) {
+	minCodeCache, minDirectMemory, minStack := flags.DefaultMinCodeCache, flags.DefaultMinDirectMemory, flags.DefaultMinStack
+	if c.MinCodeCache != nil {
+		minCodeCache = *c.MinCodeCache
+	}
+	if c.MinDirectMemory != nil {
+		minDirectMemory = *c.MinDirectMemory
+	}
+	if c.MinStack != nil {
+		minStack = *c.MinStack
+	}
+
+	deficit := func() memory.Size {
+		return required - (r.TotalMemory - c.overhead(*r))
+	}
+
+	shrink := func(region Region, size memory.Size, floor memory.Size, step memory.Size) memory.Size {
+		if deficit() <= 0 || r.Sources[region] == SourceJVMOptions || size <= floor {
+			return size
+		}
+
+		reduced := size - step
+		if reduced < floor {
+			reduced = floor
+		}
+
+		r.Sources[region] = SourceReduced
+		return reduced
+	}
+
+	if r.IsPresent(RegionCodeCacheTotal) {
+		r.CodeCacheTotal = memory.CodeCacheTotal(shrink(RegionCodeCacheTotal, memory.Size(r.CodeCacheTotal), memory.Size(minCodeCache), deficit()))
+	} else {
+		r.ReservedCodeCache = memory.ReservedCodeCache(shrink(RegionReservedCodeCache, memory.Size(r.ReservedCodeCache), memory.Size(minCodeCache), deficit()))
+	}
+
+	if r.ThreadCount > 0 {
+		step := (deficit() + memory.Size(r.ThreadCount) - 1) / memory.Size(r.ThreadCount)
+		step = (step + memory.Kibi - 1) / memory.Kibi * memory.Kibi
+		r.Stack = memory.Stack(shrink(RegionStack, memory.Size(r.Stack), memory.Size(minStack), step))
+	}
+
+	r.MaxDirectMemory = memory.MaxDirectMemory(shrink(RegionMaxDirectMemory, memory.Size(r.MaxDirectMemory), memory.Size(minDirectMemory), deficit()))
+}
+
+// ram returns the physical memory the JVM uses to size the heap: total memory, limited by -XX:MaxRAM.
+func (c Calculator) ram(j *flags.JVMOptions) float64 {
+	ram := memory.Size(*c.TotalMemory)
+
+	if j.MaxRAM != nil && memory.Size(*j.MaxRAM) < ram {
+		ram = memory.Size(*j.MaxRAM)
+	}
+
+	return float64(ram)
+}
+
+// ramHeap returns the heap the JVM would size from -XX:MaxRAM, -XX:MaxRAMPercentage and -XX:MinRAMPercentage, if any
+// of them are configured.
+func (c Calculator) ramHeap(j *flags.JVMOptions) (memory.MaxHeap, bool) {
+	if j.MaxRAM == nil && j.MaxRAMPercentage == nil && j.MinRAMPercentage == nil {
+		return 0, false
+	}
+
+	max, min := memory.DefaultMaxRAMPercentage, memory.DefaultMinRAMPercentage
+	if j.MaxRAMPercentage != nil {
+		max = *j.MaxRAMPercentage
+	}
+	if j.MinRAMPercentage != nil {
+		min = *j.MinRAMPercentage
+	}
+
+	ram := c.ram(j)
+	maxHeap := memory.Size(ram * float64(max) / 100)
+	minHeap := memory.Size(ram * float64(min) / 100)
+
+	if minHeap < ergonomicMaxHeap {
+		return memory.MaxHeap(minHeap), true
+	}
+
+	if maxHeap < ergonomicMaxHeap {
+		return memory.MaxHeap(ergonomicMaxHeap), true
+	}
+
+	return memory.MaxHeap(maxHeap), true
 }
 
-func (c Calculator) heap(overhead memory.Size) memory.MaxHeap {
-	return memory.MaxHeap(memory.Size(*c.TotalMemory) - overhead)
+// percentage returns size as a percentage of total, rounded down to two decimal places so that the JVM never sizes a
+// region larger than calculated.
+func percentage(size memory.Size, total memory.Size) float64 {
+	return math.Floor(float64(size)/float64(total)*10000) / 100
 }
 
 func (c Calculator) metaspace() memory.MaxMetaspace {
-	return memory.MaxMetaspace((*c.LoadedClassCount * 5800) + 14000000)
+	return memory.MaxMetaspace((memory.Size(*c.LoadedClassCount) * MetaspacePerClass) + MetaspaceBase)
 }
 
-func (c Calculator) overhead(headRoom memory.Size, directMemory *memory.MaxDirectMemory, metaspace *memory.MaxMetaspace, reservedCodeCache *memory.ReservedCodeCache, stack *memory.Stack) memory.Size {
-	return headRoom +
-		memory.Size(*directMemory) +
-		memory.Size(*metaspace) +
-		memory.Size(*reservedCodeCache) +
-		memory.Size(int64(*stack)*int64(*c.ThreadCount))
+func (c Calculator) overhead(r Result) memory.Size {
+	return r.HeadRoom +
+		r.ClassMemory +
+		memory.Size(r.CodeCacheTotal) +
+		memory.Size(r.CompressedClassSpace) +
+		memory.Size(r.MaxDirectMemory) +
+		memory.Size(r.MaxMetaspace) +
+		memory.Size(r.MaxPermSize) +
+		memory.Size(r.ReservedCodeCache) +
+		memory.Size(r.SharedClassCache) +
+		r.TotalStack() +
+		r.InternalStack() +
+		r.CompilerArena
 }
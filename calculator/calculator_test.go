@@ -17,10 +17,13 @@
 package calculator_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
 	. "github.com/onsi/gomega"
 	"github.com/sclevine/spec"
@@ -53,6 +56,74 @@ func TestCalculator(t *testing.T) {
 			))
 		})
 
+		it("returns typed result", func() {
+			h := memory.MaxHeap(100 * memory.Mibi)
+			c.JvmOptions.MaxHeap = &h
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.HeadRoom).To(Equal(memory.Size(0)))
+			g.Expect(r.MaxDirectMemory).To(Equal(memory.DefaultMaxDirectMemory))
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(100 * memory.Mibi)))
+			g.Expect(r.MaxMetaspace).To(Equal(memory.MaxMetaspace(19800000)))
+			g.Expect(r.ReservedCodeCache).To(Equal(memory.DefaultReservedCodeCache))
+			g.Expect(r.Stack).To(Equal(memory.DefaultStack))
+			g.Expect(r.Overhead).To(Equal(memory.Size(292429760)))
+			g.Expect(r.Sources).To(Equal(map[calculator.Region]calculator.Source{
+				calculator.RegionMaxDirectMemory:   calculator.SourceDefault,
+				calculator.RegionMaxHeap:           calculator.SourceJVMOptions,
+				calculator.RegionMaxMetaspace:      calculator.SourceCalculated,
+				calculator.RegionReservedCodeCache: calculator.SourceDefault,
+				calculator.RegionStack:             calculator.SourceDefault,
+			}))
+		})
+
+		it("uses fractional head room percentage", func() {
+			*c.HeadRoom = 2.5
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.HeadRoom).To(Equal(memory.Size(13107200)))
+		})
+
+		it("uses absolute head room", func() {
+			s := flags.HeadRoomSize(64 * memory.Mibi)
+			c.HeadRoomSize = &s
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.HeadRoom).To(Equal(memory.Size(64 * memory.Mibi)))
+		})
+
+		it("uses larger of head room percentage and size", func() {
+			*c.HeadRoom = 2.5
+			s := flags.HeadRoomSize(10 * memory.Mibi)
+			c.HeadRoomSize = &s
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.HeadRoom).To(Equal(memory.Size(13107200)))
+
+			s = flags.HeadRoomSize(20 * memory.Mibi)
+
+			r, err = c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.HeadRoom).To(Equal(memory.Size(20 * memory.Mibi)))
+		})
+
+		it("returns error if head room is greater than total memory", func() {
+			s := flags.HeadRoomSize(memory.Gibi)
+			c.HeadRoomSize = &s
+
+			_, err := c.CalculateResult()
+			g.Expect(err).To(MatchError("head room 1G is greater than total memory 500M"))
+		})
+
 		it("uses configured direct memory", func() {
 			d := memory.MaxDirectMemory(memory.Mibi)
 			c.JvmOptions.MaxDirectMemory = &d
@@ -113,12 +184,521 @@ func TestCalculator(t *testing.T) {
 			))
 		})
 
+		it("uses aliased values as configured", func() {
+			g.Expect(c.JvmOptions.Set("-XX:MaxHeapSize=1m -XX:ThreadStackSize=256")).To(Succeed())
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(r.Stack).To(Equal(memory.Stack(256 * memory.Kibi)))
+			g.Expect(r.Flags()).To(ConsistOf(
+				memory.DefaultMaxDirectMemory,
+				memory.MaxMetaspace(19800000),
+				memory.DefaultReservedCodeCache,
+			))
+		})
+
+		it("uses configured initial heap", func() {
+			i := memory.InitialHeap(memory.Mibi)
+			c.JvmOptions.InitialHeap = &i
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(r.InitialHeap).To(Equal(i))
+			g.Expect(r.IsFixed(calculator.RegionInitialHeap)).To(BeTrue())
+			g.Expect(r.Flags()).NotTo(ContainElement(i))
+		})
+
+		it("calculates initial heap as percentage of heap", func() {
+			i := flags.InitialHeapPercentage(50)
+			c.InitialHeapPercentage = &i
+
+			g.Expect(c.Calculate()).To(ConsistOf(
+				memory.DefaultMaxDirectMemory,
+				memory.MaxMetaspace(19800000),
+				memory.DefaultReservedCodeCache,
+				memory.DefaultStack,
+				memory.MaxHeap(231858240),
+				memory.InitialHeap(115929120),
+			))
+		})
+
+		it("does not calculate initial heap by default", func() {
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionInitialHeap))
+		})
+
+		it("returns error if configured initial heap is larger than heap", func() {
+			i := memory.InitialHeap(400 * memory.Mibi)
+			c.JvmOptions.InitialHeap = &i
+
+			_, err := c.CalculateResult()
+			g.Expect(err).To(MatchError("initial heap -Xms400M is greater than max heap -Xmx226424K"))
+		})
+
+		it("includes compressed class space in metaspace", func() {
+			g.Expect(c.JvmOptions.Set("-XX:MaxMetaspaceSize=100M -XX:CompressedClassSpaceSize=50M")).To(Succeed())
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionCompressedClassSpace))
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(140 * memory.Mibi)))
+		})
+
+		it("returns error if compressed class space is greater than metaspace", func() {
+			g.Expect(c.JvmOptions.Set("-XX:MaxMetaspaceSize=100M -XX:CompressedClassSpaceSize=200M")).To(Succeed())
+
+			_, err := c.CalculateResult()
+			g.Expect(err).To(MatchError("compressed class space -XX:CompressedClassSpaceSize=200M is greater than max metaspace -XX:MaxMetaspaceSize=100M"))
+		})
+
+		it("raises calculated metaspace to compressed class space", func() {
+			g.Expect(c.JvmOptions.Set("-XX:CompressedClassSpaceSize=128M")).To(Succeed())
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(r.MaxMetaspace).To(Equal(memory.MaxMetaspace(128 * memory.Mibi)))
+			g.Expect(r.Sources[calculator.RegionMaxMetaspace]).To(Equal(calculator.SourceCalculated))
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionCompressedClassSpace))
+		})
+
+		it("reserves compressed class space separately if class pointers are uncompressed", func() {
+			g.Expect(c.JvmOptions.Set("-XX:MaxMetaspaceSize=100M -XX:CompressedClassSpaceSize=50M -XX:-UseCompressedClassPointers")).To(Succeed())
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(r.CompressedClassSpace).To(Equal(memory.CompressedClassSpace(50 * memory.Mibi)))
+			g.Expect(r.IsFixed(calculator.RegionCompressedClassSpace)).To(BeTrue())
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(90 * memory.Mibi)))
+		})
+
+		it("uses permanent generation instead of metaspace for Java 7", func() {
+			v := flags.JVMVersion(7)
+			c.JvmVersion = &v
+
+			g.Expect(c.Calculate()).To(ConsistOf(
+				memory.DefaultMaxDirectMemory,
+				memory.MaxPermSize(19800000),
+				memory.ReservedCodeCache(48*memory.Mibi),
+				memory.DefaultStack,
+				memory.MaxHeap(433184832),
+			))
+		})
+
+		it("uses version and vendor from release", func() {
+			c.Release = &jvm.Release{Vendor: jvm.VendorGraalVM, Version: 7}
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.JVMVendor).To(Equal(jvm.VendorGraalVM))
+			g.Expect(r.JVMVersion).To(Equal(7))
+			g.Expect(r.Sources).To(HaveKey(calculator.RegionMaxPermSize))
+		})
+
+		it("prefers configured version to release", func() {
+			v := flags.JVMVersion(11)
+			c.JvmVersion = &v
+			c.Release = &jvm.Release{Vendor: jvm.VendorHotSpot, Version: 7}
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.JVMVersion).To(Equal(11))
+			g.Expect(r.Sources).To(HaveKey(calculator.RegionMaxMetaspace))
+		})
+
+		it("defaults to HotSpot if release is unknown", func() {
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.JVMVendor).To(Equal(jvm.VendorHotSpot))
+			g.Expect(r.JVMVersion).To(Equal(0))
+		})
+
+		it("uses OpenJ9 regions", func() {
+			v := flags.JVMVendor(jvm.VendorOpenJ9)
+			c.JvmVendor = &v
+
+			g.Expect(c.Calculate()).To(ConsistOf(
+				memory.DefaultMaxDirectMemory,
+				memory.DefaultCodeCacheTotal,
+				memory.DefaultStack,
+				memory.MaxHeap(215081024),
+			))
+		})
+
+		it("uses configured OpenJ9 regions", func() {
+			c.Release = &jvm.Release{Vendor: jvm.VendorOpenJ9, Version: 11}
+			t := memory.CodeCacheTotal(128 * memory.Mibi)
+			c.JvmOptions.CodeCacheTotal = &t
+			s := memory.SharedClassCache(64 * memory.Mibi)
+			c.JvmOptions.SharedClassCache = &s
+			m := memory.MaxMetaspace(memory.Mibi)
+			c.JvmOptions.MaxMetaspace = &m
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.ClassMemory).To(Equal(memory.Size(19800000)))
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(282189888)))
+			g.Expect(r.Sources).To(Equal(map[calculator.Region]calculator.Source{
+				calculator.RegionClassMemory:      calculator.SourceCalculated,
+				calculator.RegionCodeCacheTotal:   calculator.SourceJVMOptions,
+				calculator.RegionMaxDirectMemory:  calculator.SourceDefault,
+				calculator.RegionMaxHeap:          calculator.SourceCalculated,
+				calculator.RegionSharedClassCache: calculator.SourceJVMOptions,
+				calculator.RegionStack:            calculator.SourceDefault,
+			}))
+			g.Expect(r.Flags()).To(ConsistOf(memory.DefaultMaxDirectMemory, memory.DefaultStack, memory.MaxHeap(282189888)))
+		})
+
+		it("uses configured permanent generation for Java 7", func() {
+			v := flags.JVMVersion(7)
+			c.JvmVersion = &v
+			m := memory.MaxMetaspace(memory.Mibi)
+			c.JvmOptions.MaxMetaspace = &m
+			p := memory.MaxPermSize(64 * memory.Mibi)
+			c.JvmOptions.MaxPermSize = &p
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxPermSize).To(Equal(p))
+			g.Expect(r.IsFixed(calculator.RegionMaxPermSize)).To(BeTrue())
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionMaxMetaspace))
+		})
+
+		it("uses tiered compilation code cache for Java 7 if configured", func() {
+			v := flags.JVMVersion(7)
+			c.JvmVersion = &v
+			t := memory.TieredCompilation(true)
+			c.JvmOptions.TieredCompilation = &t
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.ReservedCodeCache).To(Equal(memory.DefaultReservedCodeCache))
+		})
+
+		it("uses smaller code cache if tiered compilation is disabled", func() {
+			v := flags.JVMVersion(8)
+			c.JvmVersion = &v
+			t := memory.TieredCompilation(false)
+			c.JvmOptions.TieredCompilation = &t
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.ReservedCodeCache).To(Equal(memory.ReservedCodeCache(48 * memory.Mibi)))
+			g.Expect(r.IsFixed(calculator.RegionReservedCodeCache)).To(BeFalse())
+		})
+
+		it("reserves garbage collector overhead from calculated heap", func() {
+			gc := memory.GarbageCollectorG1
+			c.JvmOptions.GarbageCollector = &gc
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(210780218)))
+			g.Expect(r.GCOverhead).To(Equal(memory.Size(21078022)))
+			g.Expect(r.Sources).To(HaveKeyWithValue(calculator.RegionGCOverhead, calculator.SourceCalculated))
+			g.Expect(r.Overhead).To(Equal(memory.Size(292429760 + 21078022)))
+			g.Expect(r.Unallocated()).To(Equal(memory.Size(0)))
+		})
+
+		it("reserves garbage collector overhead for configured heap", func() {
+			gc := memory.GarbageCollectorParallel
+			c.JvmOptions.GarbageCollector = &gc
+			h := memory.MaxHeap(100 * memory.Mibi)
+			c.JvmOptions.MaxHeap = &h
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.GCOverhead).To(Equal(memory.Size(3145728)))
+			g.Expect(r.Overhead).To(Equal(memory.Size(292429760 + 3145728)))
+		})
+
+		it("returns error if configured heap and garbage collector overhead are too large", func() {
+			gc := memory.GarbageCollectorG1
+			c.JvmOptions.GarbageCollector = &gc
+			h := memory.MaxHeap(210 * memory.Mibi)
+			c.JvmOptions.MaxHeap = &h
+
+			_, err := c.CalculateResult()
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+			g.Expect(e.Required).To(Equal(memory.Size(292429760 + 220200960 + 22020096)))
+		})
+
+		it("does not reserve garbage collector overhead if no collector is selected", func() {
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.GCOverhead).To(Equal(memory.Size(0)))
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionGCOverhead))
+		})
+
+		it("does not reserve garbage collector overhead for OpenJ9", func() {
+			v := flags.JVMVendor(jvm.VendorOpenJ9)
+			c.JvmVendor = &v
+			gc := memory.GarbageCollectorG1
+			c.JvmOptions.GarbageCollector = &gc
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionGCOverhead))
+		})
+
+		it("reserves internal thread stacks and compiler arena for CPU count", func() {
+			cpus := flags.CPUCount(2)
+			c.CPUCount = &cpus
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.InternalThreadCount).To(Equal(14))
+			g.Expect(r.InternalStack()).To(Equal(memory.Size(14 * memory.Mibi)))
+			g.Expect(r.CompilerArena).To(Equal(memory.Size(32 * memory.Mibi)))
+			g.Expect(r.Sources).To(HaveKeyWithValue(calculator.RegionInternalStack, calculator.SourceCalculated))
+			g.Expect(r.Sources).To(HaveKeyWithValue(calculator.RegionCompilerArena, calculator.SourceCalculated))
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(231858240 - 46*memory.Mibi)))
+		})
+
+		it("does not reserve internal threads if CPU count is unknown", func() {
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.InternalStack()).To(Equal(memory.Size(0)))
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionInternalStack))
+			g.Expect(r.Sources).NotTo(HaveKey(calculator.RegionCompilerArena))
+		})
+
+		it("expresses heap as percentage of total memory", func() {
+			m := flags.HeapModePercentage
+			c.HeapMode = &m
+			p := flags.InitialHeapPercentage(50)
+			c.InitialHeapPercentage = &p
+
+			g.Expect(c.Calculate()).To(Equal([]fmt.Stringer{
+				memory.DefaultMaxDirectMemory,
+				memory.MaxMetaspace(19800000),
+				memory.DefaultReservedCodeCache,
+				memory.DefaultStack,
+				memory.MaxRAMPercentage(44.22),
+				memory.MinRAMPercentage(44.22),
+				memory.InitialRAMPercentage(22.11),
+			}))
+		})
+
+		it("does not express configured heap as percentage", func() {
+			m := flags.HeapModePercentage
+			c.HeapMode = &m
+			h := memory.MaxHeap(100 * memory.Mibi)
+			c.JvmOptions.MaxHeap = &h
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxRAMPercentage).To(Equal(memory.MaxRAMPercentage(0)))
+		})
+
+		it("returns error if heap is expressed as percentage for Java 7", func() {
+			m := flags.HeapModePercentage
+			c.HeapMode = &m
+			v := flags.JVMVersion(7)
+			c.JvmVersion = &v
+
+			_, err := c.CalculateResult()
+			g.Expect(err).To(MatchError("--heap-mode=percentage requires Java 8 or later"))
+		})
+
+		it("uses heap configured with RAM percentages", func() {
+			m := flags.TotalMemory(2 * memory.Gibi)
+			c.TotalMemory = &m
+			x := memory.MaxRAMPercentage(60)
+			c.JvmOptions.MaxRAMPercentage = &x
+			i := memory.InitialRAMPercentage(10)
+			c.JvmOptions.InitialRAMPercentage = &i
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(1288490188)))
+			g.Expect(r.IsFixed(calculator.RegionMaxHeap)).To(BeTrue())
+			g.Expect(r.InitialHeap).To(Equal(memory.InitialHeap(214748364)))
+			g.Expect(r.IsFixed(calculator.RegionInitialHeap)).To(BeTrue())
+		})
+
+		it("uses MinRAMPercentage for small amounts of memory", func() {
+			x := memory.MaxRAMPercentage(10)
+			c.JvmOptions.MaxRAMPercentage = &x
+			n := memory.MinRAMPercentage(20)
+			c.JvmOptions.MinRAMPercentage = &n
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(104857600)))
+		})
+
+		it("limits RAM used for heap with MaxRAM", func() {
+			m := flags.TotalMemory(2 * memory.Gibi)
+			c.TotalMemory = &m
+			r := memory.MaxRAM(memory.Gibi)
+			c.JvmOptions.MaxRAM = &r
+
+			result, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(result.MaxHeap).To(Equal(memory.MaxHeap(268435456)))
+		})
+
+		it("does not reduce regions if heap is larger than minimum", func() {
+			h := flags.MinHeap(100 * memory.Mibi)
+			c.MinHeap = &h
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(231858240)))
+			g.Expect(r.Reduced()).To(BeEmpty())
+		})
+
+		it("reduces code cache to fit minimum heap", func() {
+			h := flags.MinHeap(300 * memory.Mibi)
+			c.MinHeap = &h
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(300 * memory.Mibi)))
+			g.Expect(r.ReservedCodeCache).To(Equal(memory.ReservedCodeCache(168943680)))
+			g.Expect(r.Sources).To(HaveKeyWithValue(calculator.RegionReservedCodeCache, calculator.SourceReduced))
+			g.Expect(r.Reduced()).To(Equal([]calculator.Region{calculator.RegionReservedCodeCache}))
+		})
+
+		it("reduces code cache, stack and direct memory to fit minimum heap", func() {
+			h := flags.MinHeap(440 * memory.Mibi)
+			c.MinHeap = &h
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.MaxHeap).To(Equal(memory.MaxHeap(440 * memory.Mibi)))
+			g.Expect(r.ReservedCodeCache).To(Equal(memory.ReservedCodeCache(flags.DefaultMinCodeCache)))
+			g.Expect(r.Stack).To(Equal(memory.Stack(flags.DefaultMinStack)))
+			g.Expect(r.MaxDirectMemory).To(Equal(memory.MaxDirectMemory(6938688)))
+			g.Expect(r.Reduced()).To(Equal([]calculator.Region{
+				calculator.RegionReservedCodeCache, calculator.RegionStack, calculator.RegionMaxDirectMemory,
+			}))
+		})
+
+		it("reduces regions no further than configured minimums", func() {
+			h := flags.MinHeap(440 * memory.Mibi)
+			c.MinHeap = &h
+			cc := flags.MinCodeCache(64 * memory.Mibi)
+			c.MinCodeCache = &cc
+			s := flags.MinStack(512 * memory.Kibi)
+			c.MinStack = &s
+
+			_, err := c.CalculateResult()
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+			g.Expect(e.Result.ReservedCodeCache).To(Equal(memory.ReservedCodeCache(cc)))
+			g.Expect(e.Result.Stack).To(Equal(memory.Stack(s)))
+			g.Expect(e.Result.MaxDirectMemory).To(Equal(memory.MaxDirectMemory(flags.DefaultMinDirectMemory)))
+		})
+
+		it("does not reduce regions without minimum heap", func() {
+			h := flags.DefaultMinHeap
+			c.MinHeap = &h
+			m := flags.TotalMemory(250 * memory.Mibi)
+			c.TotalMemory = &m
+
+			_, err := c.CalculateResult()
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+			g.Expect(e.Result.Reduced()).To(BeEmpty())
+		})
+
+		it("does not reduce configured regions", func() {
+			h := flags.MinHeap(300 * memory.Mibi)
+			c.MinHeap = &h
+			cc := memory.ReservedCodeCache(240 * memory.Mibi)
+			c.JvmOptions.ReservedCodeCache = &cc
+
+			_, err := c.CalculateResult()
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+			g.Expect(e.Result.ReservedCodeCache).To(Equal(cc))
+			g.Expect(e.Result.Stack).To(Equal(memory.Stack(flags.DefaultMinStack)))
+		})
+
+		it("returns error if minimum heap does not fit with reduced regions", func() {
+			h := flags.MinHeap(450 * memory.Mibi)
+			c.MinHeap = &h
+
+			_, err := c.CalculateResult()
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+			g.Expect(e.Required).To(Equal(memory.Size(528883648)))
+		})
+
+		it("does not apply minimum heap to configured heap", func() {
+			h := flags.MinHeap(300 * memory.Mibi)
+			c.MinHeap = &h
+			m := memory.MaxHeap(100 * memory.Mibi)
+			c.JvmOptions.MaxHeap = &m
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(r.Reduced()).To(BeEmpty())
+		})
+
+		it("accepts young generation smaller than heap", func() {
+			g.Expect(c.JvmOptions.Set("-Xmn100M -XX:NewSize=200M -XX:MaxNewSize=1G -XX:NewRatio=1")).To(Succeed())
+
+			_, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("returns error if young generation is not smaller than heap", func() {
+			g.Expect(c.JvmOptions.Set("-Xmn100M -XX:NewSize=300M")).To(Succeed())
+
+			_, err := c.CalculateResult()
+			g.Expect(err).To(MatchError("max heap -Xmx226424K cannot accommodate young generation -XX:NewSize=300M"))
+		})
+
+		it("returns error if young generation is not smaller than configured heap", func() {
+			g.Expect(c.JvmOptions.Set("-Xmx100M -Xmn100M")).To(Succeed())
+
+			_, err := c.CalculateResult()
+			g.Expect(err).To(MatchError("max heap -Xmx100M cannot accommodate young generation -Xmn100M"))
+		})
+
 		it("returns error if overhead is too large", func() {
 			m := memory.MaxMetaspace(500 * memory.Mibi)
 			c.JvmOptions.MaxMetaspace = &m
 
 			_, err := c.Calculate()
 			g.Expect(err).To(HaveOccurred())
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+			g.Expect(e.Available).To(Equal(memory.Size(500 * memory.Mibi)))
+			g.Expect(e.Required).To(Equal(memory.Size(760 * memory.Mibi)))
+			g.Expect(e.Result.MaxMetaspace).To(Equal(m))
+			g.Expect(e.Result.ThreadCount).To(Equal(10))
 		})
 
 		it("returns error if configured heap is too large", func() {
@@ -127,6 +707,10 @@ func TestCalculator(t *testing.T) {
 
 			_, err := c.Calculate()
 			g.Expect(err).To(HaveOccurred())
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+			g.Expect(e.Result.MaxHeap).To(Equal(h))
 		})
 	})
 }
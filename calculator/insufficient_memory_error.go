@@ -0,0 +1,63 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+// InsufficientMemoryError is returned when the memory required by the configuration is greater than the memory
+// available.  Result contains the regions calculated before the failure; the heap is only present if it was considered.
+type InsufficientMemoryError struct {
+	Available memory.Size
+	Required  memory.Size
+	Result    Result
+}
+
+func (i *InsufficientMemoryError) Error() string {
+	r := i.Result
+
+	var regions []string
+	for _, c := range []struct {
+		region   Region
+		value    string
+		optional bool
+	}{
+		{RegionMaxDirectMemory, r.MaxDirectMemory.String(), false},
+		{RegionMaxHeap, r.MaxHeap.String(), true},
+		{RegionGCOverhead, fmt.Sprintf("%s garbage collector", r.GCOverhead), true},
+		{RegionMaxMetaspace, r.MaxMetaspace.String(), r.IsPresent(RegionMaxPermSize) || r.IsPresent(RegionClassMemory)},
+		{RegionMaxPermSize, r.MaxPermSize.String(), true},
+		{RegionClassMemory, fmt.Sprintf("%s class memory", r.ClassMemory), true},
+		{RegionCompressedClassSpace, r.CompressedClassSpace.String(), true},
+		{RegionReservedCodeCache, r.ReservedCodeCache.String(), r.IsPresent(RegionCodeCacheTotal)},
+		{RegionCodeCacheTotal, r.CodeCacheTotal.String(), true},
+		{RegionSharedClassCache, r.SharedClassCache.String(), true},
+		{RegionCompilerArena, fmt.Sprintf("%s compiler arena", r.CompilerArena), true},
+		{RegionInternalStack, fmt.Sprintf("%s x %d internal threads", InternalThreadStack, r.InternalThreadCount), true},
+	} {
+		if !c.optional || r.IsPresent(c.region) {
+			regions = append(regions, c.value)
+		}
+	}
+
+	return fmt.Sprintf("required memory %s is greater than %s available for allocation: %s, %s x %d threads",
+		i.Required, i.Available, strings.Join(regions, ", "), r.Stack, r.ThreadCount)
+}
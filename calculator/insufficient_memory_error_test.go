@@ -0,0 +1,84 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestInsufficientMemoryError(t *testing.T) {
+	spec.Run(t, "InsufficientMemoryError", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var e *calculator.InsufficientMemoryError
+
+		it.Before(func() {
+			e = &calculator.InsufficientMemoryError{
+				Available: memory.Size(memory.Gibi),
+				Required:  memory.Size(2 * memory.Gibi),
+				Result: calculator.Result{
+					MaxDirectMemory:   memory.MaxDirectMemory(memory.Mibi),
+					MaxMetaspace:      memory.MaxMetaspace(memory.Mibi),
+					ReservedCodeCache: memory.ReservedCodeCache(memory.Mibi),
+					Sources:           map[calculator.Region]calculator.Source{},
+					Stack:             memory.Stack(memory.Mibi),
+					ThreadCount:       10,
+				},
+			}
+		})
+
+		it("formats without heap", func() {
+			g.Expect(e.Error()).To(Equal("required memory 2G is greater than 1G available for allocation: " +
+				"-XX:MaxDirectMemorySize=1M, -XX:MaxMetaspaceSize=1M, -XX:ReservedCodeCacheSize=1M, -Xss1M x 10 threads"))
+		})
+
+		it("formats OpenJ9 regions", func() {
+			e.Result.ClassMemory = memory.Size(memory.Mibi)
+			e.Result.CodeCacheTotal = memory.CodeCacheTotal(memory.Mibi)
+			e.Result.Sources[calculator.RegionClassMemory] = calculator.SourceCalculated
+			e.Result.Sources[calculator.RegionCodeCacheTotal] = calculator.SourceDefault
+
+			g.Expect(e.Error()).To(Equal("required memory 2G is greater than 1G available for allocation: " +
+				"-XX:MaxDirectMemorySize=1M, 1M class memory, -Xcodecachetotal1M, -Xss1M x 10 threads"))
+		})
+
+		it("formats internal threads", func() {
+			e.Result.CompilerArena = memory.Size(32 * memory.Mibi)
+			e.Result.InternalThreadCount = 14
+			e.Result.Sources[calculator.RegionCompilerArena] = calculator.SourceCalculated
+			e.Result.Sources[calculator.RegionInternalStack] = calculator.SourceCalculated
+
+			g.Expect(e.Error()).To(Equal("required memory 2G is greater than 1G available for allocation: " +
+				"-XX:MaxDirectMemorySize=1M, -XX:MaxMetaspaceSize=1M, -XX:ReservedCodeCacheSize=1M, 32M compiler arena, " +
+				"1M x 14 internal threads, -Xss1M x 10 threads"))
+		})
+
+		it("formats with heap", func() {
+			e.Result.MaxHeap = memory.MaxHeap(memory.Gibi)
+			e.Result.Sources[calculator.RegionMaxHeap] = calculator.SourceJVMOptions
+
+			g.Expect(e.Error()).To(Equal("required memory 2G is greater than 1G available for allocation: " +
+				"-XX:MaxDirectMemorySize=1M, -Xmx1G, -XX:MaxMetaspaceSize=1M, -XX:ReservedCodeCacheSize=1M, -Xss1M x 10 threads"))
+		})
+	})
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+type Region string
+
+const (
+	RegionClassMemory          = Region("class_memory")
+	RegionCodeCacheTotal       = Region("code_cache_total")
+	RegionCompilerArena        = Region("compiler_arena")
+	RegionCompressedClassSpace = Region("compressed_class_space")
+	RegionGCOverhead           = Region("gc_overhead")
+	RegionInitialHeap          = Region("initial_heap")
+	RegionInternalStack        = Region("internal_stack")
+	RegionMaxDirectMemory      = Region("max_direct_memory")
+	RegionMaxHeap              = Region("max_heap")
+	RegionMaxMetaspace         = Region("max_metaspace")
+	RegionMaxPermSize          = Region("max_perm_size")
+	RegionReservedCodeCache    = Region("reserved_code_cache")
+	RegionSharedClassCache     = Region("shared_class_cache")
+	RegionStack                = Region("stack")
+)
+
+// Source describes where the value of a region came from.
+type Source string
+
+const (
+	SourceCalculated = Source("calculated")
+	SourceDefault    = Source("default")
+	SourceJVMOptions = Source("jvm-options")
+
+	// SourceReduced is a default or calculated value that was reduced to fit the minimum heap.
+	SourceReduced = Source("reduced")
+)
+
+// Result is the outcome of a calculation.  It contains the size of every region, whether or not it was specified by
+// the user, so that callers can inspect the configuration without parsing JVM flags.
+type Result struct {
+	// ClassMemory and CodeCacheTotal are only present in Sources for OpenJ9, in which case they replace MaxMetaspace
+	// and ReservedCodeCache.  ClassMemory has no flag as OpenJ9 does not limit class metadata.
+	ClassMemory    memory.Size
+	CodeCacheTotal memory.CodeCacheTotal
+
+	// CPUCount, InternalThreadCount and CompilerArena are only set, and RegionInternalStack and RegionCompilerArena
+	// only present in Sources, if the number of CPUs is known, in which case they account for the threads the JVM
+	// starts for itself and the memory its compiler threads use.
+	CPUCount            int
+	InternalThreadCount int
+	CompilerArena       memory.Size
+
+	// CompressedClassSpace is only present in Sources if class pointers are uncompressed, in which case it is
+	// reserved in addition to metaspace.  Otherwise it is part of metaspace.
+	CompressedClassSpace memory.CompressedClassSpace
+
+	// GCOverhead is only present in Sources if a garbage collector was selected, in which case it is the native memory
+	// reserved for the collector's data structures in proportion to the heap.
+	GCOverhead memory.Size
+
+	// HeadRoom is the amount of total memory left unallocated.
+	HeadRoom memory.Size
+
+	// InitialHeap is only present in Sources if it was specified by the user or requested as a percentage of the heap.
+	InitialHeap memory.InitialHeap
+
+	// MaxRAMPercentage and InitialRAMPercentage are only set if the heap is expressed as a percentage of total memory,
+	// in which case they replace MaxHeap and InitialHeap in Flags.
+	MaxRAMPercentage     memory.MaxRAMPercentage
+	InitialRAMPercentage memory.InitialRAMPercentage
+
+	// JVMVendor and JVMVersion identify the JVM whose defaults were used.  A version of 0 is unknown.
+	JVMVendor  jvm.Vendor
+	JVMVersion int
+
+	MaxDirectMemory   memory.MaxDirectMemory
+	MaxHeap           memory.MaxHeap
+	MaxMetaspace      memory.MaxMetaspace
+	ReservedCodeCache memory.ReservedCodeCache
+
+	// MaxPermSize is only present in Sources for Java 7, in which case it replaces MaxMetaspace.
+	MaxPermSize memory.MaxPermSize
+
+	// Overhead is the amount of memory required by the head room and every non-heap region, including GCOverhead.
+	Overhead memory.Size
+
+	// SharedClassCache is only present in Sources if it was configured for OpenJ9.
+	SharedClassCache memory.SharedClassCache
+
+	// Sources records where the value of each region came from.
+	Sources map[Region]Source
+
+	// Stack is the size of a single thread stack.
+	Stack memory.Stack
+
+	ThreadCount int
+	TotalMemory memory.Size
+}
+
+// Flags returns the JVM options for every region that was not specified by the user.
+func (r Result) Flags() []fmt.Stringer {
+	var f []fmt.Stringer
+
+	for _, c := range []struct {
+		region   Region
+		flag     fmt.Stringer
+		optional bool
+	}{
+		{RegionMaxDirectMemory, r.MaxDirectMemory, false},
+		{RegionMaxMetaspace, r.MaxMetaspace, r.IsPresent(RegionMaxPermSize) || r.IsPresent(RegionClassMemory)},
+		{RegionMaxPermSize, r.MaxPermSize, true},
+		{RegionReservedCodeCache, r.ReservedCodeCache, r.IsPresent(RegionCodeCacheTotal)},
+		{RegionCodeCacheTotal, r.CodeCacheTotal, true},
+		{RegionSharedClassCache, r.SharedClassCache, true},
+		{RegionStack, r.Stack, false},
+	} {
+		if c.optional && !r.IsPresent(c.region) {
+			continue
+		}
+
+		if !r.IsFixed(c.region) {
+			f = append(f, c.flag)
+		}
+	}
+
+	if !r.IsFixed(RegionMaxHeap) {
+		if r.MaxRAMPercentage > 0 {
+			// MinRAMPercentage is used instead of MaxRAMPercentage for small amounts of memory
+			f = append(f, r.MaxRAMPercentage, memory.MinRAMPercentage(r.MaxRAMPercentage))
+		} else {
+			f = append(f, r.MaxHeap)
+		}
+	}
+
+	if r.IsPresent(RegionInitialHeap) && !r.IsFixed(RegionInitialHeap) {
+		if r.InitialRAMPercentage > 0 {
+			f = append(f, r.InitialRAMPercentage)
+		} else {
+			f = append(f, r.InitialHeap)
+		}
+	}
+
+	return f
+}
+
+// IsFixed returns whether the value of a region was specified by the user.
+func (r Result) IsFixed(region Region) bool {
+	return r.Sources[region] == SourceJVMOptions
+}
+
+// IsPresent returns whether a region applies to the calculation.
+func (r Result) IsPresent(region Region) bool {
+	_, ok := r.Sources[region]
+	return ok
+}
+
+// Reduced returns the regions that were reduced to fit the minimum heap.
+func (r Result) Reduced() []Region {
+	var reduced []Region
+
+	for _, region := range []Region{RegionCodeCacheTotal, RegionReservedCodeCache, RegionStack, RegionMaxDirectMemory} {
+		if r.Sources[region] == SourceReduced {
+			reduced = append(reduced, region)
+		}
+	}
+
+	return reduced
+}
+
+// InternalStack returns the memory required by the stacks of the JVM's internal threads.
+func (r Result) InternalStack() memory.Size {
+	return InternalThreadStack * memory.Size(r.InternalThreadCount)
+}
+
+// TotalStack returns the memory required by the stacks of all threads.
+func (r Result) TotalStack() memory.Size {
+	return memory.Size(int64(r.Stack) * int64(r.ThreadCount))
+}
+
+// Unallocated returns the memory that is neither used by the overhead nor by the heap.  This is non-zero only if the
+// heap was specified by the user.
+func (r Result) Unallocated() memory.Size {
+	return r.TotalMemory - r.Overhead - memory.Size(r.MaxHeap)
+}
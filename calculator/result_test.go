@@ -0,0 +1,90 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package calculator_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestResult(t *testing.T) {
+	spec.Run(t, "Result", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var r calculator.Result
+
+		it.Before(func() {
+			r = calculator.Result{
+				HeadRoom:          memory.Size(memory.Mibi),
+				MaxDirectMemory:   memory.MaxDirectMemory(memory.Mibi),
+				MaxHeap:           memory.MaxHeap(100 * memory.Mibi),
+				MaxMetaspace:      memory.MaxMetaspace(memory.Mibi),
+				Overhead:          memory.Size(24 * memory.Mibi),
+				ReservedCodeCache: memory.ReservedCodeCache(memory.Mibi),
+				Sources: map[calculator.Region]calculator.Source{
+					calculator.RegionMaxDirectMemory:   calculator.SourceDefault,
+					calculator.RegionMaxHeap:           calculator.SourceJVMOptions,
+					calculator.RegionMaxMetaspace:      calculator.SourceCalculated,
+					calculator.RegionReservedCodeCache: calculator.SourceJVMOptions,
+					calculator.RegionStack:             calculator.SourceDefault,
+				},
+				Stack:       memory.Stack(memory.Mibi),
+				ThreadCount: 20,
+				TotalMemory: memory.Size(128 * memory.Mibi),
+			}
+		})
+
+		it("returns flags for regions not specified by the user", func() {
+			g.Expect(r.Flags()).To(Equal([]fmt.Stringer{
+				memory.MaxDirectMemory(memory.Mibi),
+				memory.MaxMetaspace(memory.Mibi),
+				memory.Stack(memory.Mibi),
+			}))
+		})
+
+		it("returns permanent generation flag instead of metaspace", func() {
+			delete(r.Sources, calculator.RegionMaxMetaspace)
+			r.MaxPermSize = memory.MaxPermSize(memory.Mibi)
+			r.Sources[calculator.RegionMaxPermSize] = calculator.SourceCalculated
+
+			g.Expect(r.Flags()).To(Equal([]fmt.Stringer{
+				memory.MaxDirectMemory(memory.Mibi),
+				memory.MaxPermSize(memory.Mibi),
+				memory.Stack(memory.Mibi),
+			}))
+		})
+
+		it("returns whether a region is fixed", func() {
+			g.Expect(r.IsFixed(calculator.RegionMaxHeap)).To(BeTrue())
+			g.Expect(r.IsFixed(calculator.RegionMaxMetaspace)).To(BeFalse())
+		})
+
+		it("returns total stack", func() {
+			g.Expect(r.TotalStack()).To(Equal(memory.Size(20 * memory.Mibi)))
+		})
+
+		it("returns unallocated memory", func() {
+			g.Expect(r.Unallocated()).To(Equal(memory.Size(4 * memory.Mibi)))
+		})
+	})
+}
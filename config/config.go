@@ -0,0 +1,174 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/execd"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is a YAML configuration file.  Each top-level key is the name of a flag and its value the flag's argument.
+// Profiles are named sets of flags that override the top-level flags when selected.
+//
+//	thread-count: 250
+//	head-room: 5%
+//	profiles:
+//	  batch:
+//	    thread-count: 20
+//	    jvm-options: -XX:ReservedCodeCacheSize=64M
+type Config struct {
+	Flags    map[string]interface{}            `yaml:",inline"`
+	Profiles map[string]map[string]interface{} `yaml:"profiles"`
+}
+
+// Read reads a configuration file.
+func Read(path string) (Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to read config: %w", err)
+	}
+
+	var c Config
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return Config{}, fmt.Errorf("unable to parse config %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Apply sets each flag that was not specified on the command line, or in the environment, from the top-level flags
+// and the flags of a profile, if a profile is named.  JVM options are the exception: options in the configuration
+// are combined with those already specified, which take precedence.  The values are validated when the flags are, so
+// invalid values fail with the same messages.
+//
+// The JVM options that are only specified in the configuration are returned, as unlike those that are already
+// specified (typically in JAVA_OPTS or JAVA_TOOL_OPTIONS) they do not reach the JVM unless they are printed.
+func (c Config) Apply(f *pflag.FlagSet, profile string) ([]string, error) {
+	values := make(map[string]interface{})
+	for k, v := range c.Flags {
+		values[k] = v
+	}
+
+	if profile != "" {
+		p, ok := c.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %s is not defined in config", profile)
+		}
+
+		for k, v := range p {
+			values[k] = v
+		}
+	}
+
+	var names []string
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var configured []string
+	for _, name := range names {
+		if name == flags.FlagConfig || name == flags.FlagProfile || f.Lookup(name) == nil {
+			return nil, fmt.Errorf("config contains unknown flag: %s", name)
+		}
+
+		// exec.d mode determines how the environment is read, which happens before the configuration is applied
+		if name == execd.FlagExecD || name == execd.FlagExecDPath {
+			return nil, fmt.Errorf("config cannot contain flag: %s", name)
+		}
+
+		value, err := scalar(name, values[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if name == flags.FlagJVMOptions {
+			if configured, err = combine(f, value); err != nil {
+				return nil, fmt.Errorf("unable to set --%s from config: %w", name, err)
+			}
+
+			continue
+		}
+
+		if f.Changed(name) {
+			continue
+		}
+
+		if err := f.Set(name, value); err != nil {
+			return nil, fmt.Errorf("unable to set --%s from config: %w", name, err)
+		}
+	}
+
+	return configured, nil
+}
+
+// scalar returns the argument of a flag.  Lists are only accepted for JVM options, whose elements are each a single
+// option, and are quoted so that they are tokenized as such.
+func scalar(name string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string, int, float64, bool:
+		return fmt.Sprint(v), nil
+	case []interface{}:
+		if name != flags.FlagJVMOptions {
+			return "", fmt.Errorf("config value of %s must not be a list", name)
+		}
+
+		options := make([]string, len(v))
+		for i, o := range v {
+			s, err := scalar("", o)
+			if err != nil {
+				return "", fmt.Errorf("config value of %s must be a list of options: %w", name, err)
+			}
+
+			options[i] = "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		}
+
+		return strings.Join(options, " "), nil
+	default:
+		return "", fmt.Errorf("config value of %s must be a string or a number: %v", name, value)
+	}
+}
+
+// combine replaces JVM options with the options from the config followed by the original options, so that the
+// original options take precedence, and returns the options from the config that are not overridden.
+func combine(f *pflag.FlagSet, options string) ([]string, error) {
+	j, ok := f.Lookup(flags.FlagJVMOptions).Value.(*flags.JVMOptions)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %s", f.Lookup(flags.FlagJVMOptions).Value.Type())
+	}
+
+	var combined flags.JVMOptions
+	if err := combined.Set(options); err != nil {
+		return nil, err
+	}
+
+	configured := combined.Excluding(j)
+
+	if err := combined.Append(j.Tokens...); err != nil {
+		return nil, err
+	}
+
+	*j = combined
+	return configured, nil
+}
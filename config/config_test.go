@@ -0,0 +1,193 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/config"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/execd"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/spf13/pflag"
+)
+
+func TestConfig(t *testing.T) {
+	spec.Run(t, "Config", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var (
+			path string
+			f    *pflag.FlagSet
+			h    flags.HeadRoom
+			j    flags.JVMOptions
+			tc   flags.ThreadCount
+		)
+
+		read := func(content string) config.Config {
+			p := filepath.Join(path, "config.yml")
+			g.Expect(ioutil.WriteFile(p, []byte(content), 0644)).To(Succeed())
+
+			c, err := config.Read(p)
+			g.Expect(err).NotTo(HaveOccurred())
+			return c
+		}
+
+		it.Before(func() {
+			var err error
+			path, err = ioutil.TempDir("", "config")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			h, j, tc = flags.DefaultHeadRoom, flags.JVMOptions{}, flags.DefaultThreadCount
+
+			f = pflag.NewFlagSet("test", pflag.ContinueOnError)
+			f.Var(&h, flags.FlagHeadRoom, "")
+			f.Var(&j, flags.FlagJVMOptions, "")
+			f.Var(&tc, flags.FlagThreadCount, "")
+			f.Bool(execd.FlagExecD, false, "")
+			f.String(execd.FlagExecDPath, "", "")
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("sets flags", func() {
+			c := read("thread-count: 250\nhead-room: 2.5\n")
+
+			g.Expect(c.Apply(f, "")).To(BeEmpty())
+
+			g.Expect(tc).To(Equal(flags.ThreadCount(250)))
+			g.Expect(h).To(Equal(flags.HeadRoom(2.5)))
+		})
+
+		it("overrides flags with profile", func() {
+			c := read(`thread-count: 250
+head-room: 5%
+profiles:
+  batch:
+    thread-count: 20
+`)
+
+			g.Expect(c.Apply(f, "batch")).To(BeEmpty())
+
+			g.Expect(tc).To(Equal(flags.ThreadCount(20)))
+			g.Expect(h).To(Equal(flags.HeadRoom(5)))
+		})
+
+		it("does not override specified flags", func() {
+			c := read("thread-count: 250\n")
+			g.Expect(f.Parse([]string{"--thread-count", "10"})).To(Succeed())
+
+			g.Expect(c.Apply(f, "")).To(BeEmpty())
+
+			g.Expect(tc).To(Equal(flags.ThreadCount(10)))
+		})
+
+		it("combines JVM options with specified JVM options", func() {
+			c := read("jvm-options: -Xss256K -XX:ReservedCodeCacheSize=64M\n")
+			g.Expect(f.Parse([]string{"--jvm-options", "-Xss512K"})).To(Succeed())
+
+			g.Expect(c.Apply(f, "")).To(Equal([]string{"-XX:ReservedCodeCacheSize=64M"}))
+
+			g.Expect(*j.Stack).To(Equal(memory.Stack(512 * memory.Kibi)))
+			g.Expect(*j.ReservedCodeCache).To(Equal(memory.ReservedCodeCache(64 * memory.Mibi)))
+			g.Expect(j.Tokens).To(Equal([]string{"-Xss256K", "-XX:ReservedCodeCacheSize=64M", "-Xss512K"}))
+		})
+
+		it("returns JVM options only specified in config", func() {
+			c := read("jvm-options: -XX:ReservedCodeCacheSize=64M -javaagent:agent.jar\n")
+
+			g.Expect(c.Apply(f, "")).To(Equal([]string{"-XX:ReservedCodeCacheSize=64M", "-javaagent:agent.jar"}))
+			g.Expect(*j.ReservedCodeCache).To(Equal(memory.ReservedCodeCache(64 * memory.Mibi)))
+		})
+
+		it("accepts a list of JVM options", func() {
+			c := read("jvm-options: [-Xss512k, -Xmx300m, \"-Dgreeting=it's me\"]\n")
+
+			g.Expect(c.Apply(f, "")).To(Equal([]string{"-Xss512k", "-Xmx300m", "-Dgreeting=it's me"}))
+			g.Expect(*j.Stack).To(Equal(memory.Stack(512 * memory.Kibi)))
+			g.Expect(*j.MaxHeap).To(Equal(memory.MaxHeap(300 * memory.Mibi)))
+		})
+
+		it("returns error if value is not a scalar", func() {
+			c := read("thread-count: [10, 20]\n")
+
+			_, err := c.Apply(f, "")
+			g.Expect(err).To(MatchError("config value of thread-count must not be a list"))
+
+			c = read("head-room:\n  percentage: 5\n")
+
+			_, err = c.Apply(f, "")
+			g.Expect(err).To(MatchError(HavePrefix("config value of head-room must be a string or a number")))
+		})
+
+		it("returns error if profile is not defined", func() {
+			c := read("thread-count: 250\n")
+
+			_, err := c.Apply(f, "web")
+			g.Expect(err).To(MatchError("profile web is not defined in config"))
+		})
+
+		it("returns error if flag is unknown", func() {
+			c := read("threads: 250\n")
+
+			_, err := c.Apply(f, "")
+			g.Expect(err).To(MatchError("config contains unknown flag: threads"))
+		})
+
+		it("returns error if flag selects exec.d mode", func() {
+			c := read("exec-d: true\n")
+
+			_, err := c.Apply(f, "")
+			g.Expect(err).To(MatchError("config cannot contain flag: exec-d"))
+
+			c = read("profiles:\n  batch:\n    exec-d-path: /tmp/out.toml\n")
+
+			_, err = c.Apply(f, "batch")
+			g.Expect(err).To(MatchError("config cannot contain flag: exec-d-path"))
+		})
+
+		it("returns error if value is malformed", func() {
+			c := read("thread-count: many\n")
+
+			_, err := c.Apply(f, "")
+			g.Expect(err).To(MatchError(HavePrefix("unable to set --thread-count from config")))
+		})
+
+		it("leaves validation to flags", func() {
+			c := read("thread-count: -1\n")
+
+			g.Expect(c.Apply(f, "")).To(BeEmpty())
+			g.Expect(tc.Validate()).To(MatchError("--thread-count must be positive: -1"))
+		})
+
+		it("returns error if file is malformed", func() {
+			p := filepath.Join(path, "config.yml")
+			g.Expect(ioutil.WriteFile(p, []byte("profiles: [\n"), 0644)).To(Succeed())
+
+			_, err := config.Read(p)
+			g.Expect(err).To(HaveOccurred())
+		})
+	})
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	"github.com/spf13/pflag"
+)
+
+const (
+	HeadRoom         = "BPL_JVM_HEAD_ROOM"
+	JavaOpts         = "JAVA_OPTS"
+	JavaToolOptions  = "JAVA_TOOL_OPTIONS"
+	LoadedClassCount = "BPL_JVM_LOADED_CLASS_COUNT"
+	MemoryLimit      = "MEMORY_LIMIT"
+	ThreadCount      = "BPL_JVM_THREAD_COUNT"
+	TotalMemory      = "BPL_JVM_TOTAL_MEMORY"
+	VCAPApplication  = "VCAP_APPLICATION"
+)
+
+// Lookup returns the value of an environment variable and whether it is set.  os.LookupEnv is a Lookup.
+type Lookup func(key string) (string, bool)
+
+// Apply sets each flag that was not specified on the command line from the environment:
+//
+//	--head-room          BPL_JVM_HEAD_ROOM
+//	--jvm-options        JAVA_TOOL_OPTIONS followed by JAVA_OPTS
+//	--loaded-class-count BPL_JVM_LOADED_CLASS_COUNT
+//	--thread-count       BPL_JVM_THREAD_COUNT
+//	--total-memory       BPL_JVM_TOTAL_MEMORY, otherwise MEMORY_LIMIT, otherwise limits.mem of VCAP_APPLICATION
+//
+// Empty variables are ignored.  Flags that are set are marked as changed, so that detection is skipped for them.  As the
+// JVM always applies JAVA_TOOL_OPTIONS, before its command line, it is put in front of --jvm-options that were
+// specified on the command line.
+func Apply(f *pflag.FlagSet, lookup Lookup) error {
+	return apply(f, lookup, JavaToolOptions, JavaOpts)
+}
+
+// ApplyExecD sets flags from the environment as Apply does, except that --jvm-options are only read from
+// JAVA_TOOL_OPTIONS.  An exec.d helper can only override JAVA_TOOL_OPTIONS and JAVA_OPTS is not guaranteed to reach
+// the JVM, so options in JAVA_OPTS must not prevent the corresponding options from being calculated.
+func ApplyExecD(f *pflag.FlagSet, lookup Lookup) error {
+	return apply(f, lookup, JavaToolOptions)
+}
+
+func apply(f *pflag.FlagSet, lookup Lookup, jvmOptions ...string) error {
+	set := func(flag string, variable string, value string) error {
+		if f.Changed(flag) || value == "" {
+			return nil
+		}
+
+		if err := f.Set(flag, value); err != nil {
+			return fmt.Errorf("unable to set --%s from %s: %w", flag, variable, err)
+		}
+
+		return nil
+	}
+
+	for _, v := range []struct {
+		flag     string
+		variable string
+	}{
+		{flags.FlagHeadRoom, HeadRoom},
+		{flags.FlagLoadedClassCount, LoadedClassCount},
+		{flags.FlagThreadCount, ThreadCount},
+		{flags.FlagTotalMemory, TotalMemory},
+		{flags.FlagTotalMemory, MemoryLimit},
+	} {
+		s, _ := lookup(v.variable)
+		if err := set(v.flag, v.variable, strings.TrimSpace(s)); err != nil {
+			return err
+		}
+	}
+
+	if s, ok := lookup(VCAPApplication); ok && s != "" && !f.Changed(flags.FlagTotalMemory) {
+		m, err := ParseVCAPApplication(s)
+		if err != nil {
+			return err
+		}
+
+		if m > 0 {
+			if err := set(flags.FlagTotalMemory, VCAPApplication, m.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if f.Changed(flags.FlagJVMOptions) {
+		s, _ := lookup(JavaToolOptions)
+		return prepend(f, JavaToolOptions, strings.TrimSpace(s))
+	}
+
+	var options []string
+	for _, variable := range jvmOptions {
+		if s, _ := lookup(variable); strings.TrimSpace(s) != "" {
+			options = append(options, strings.TrimSpace(s))
+		}
+	}
+
+	return set(flags.FlagJVMOptions, strings.Join(jvmOptions, " and "), strings.Join(options, " "))
+}
+
+// prepend puts the options of a variable in front of the --jvm-options that were specified on the command line.
+func prepend(f *pflag.FlagSet, variable string, value string) error {
+	v := f.Lookup(flags.FlagJVMOptions)
+	if v == nil || value == "" {
+		return nil
+	}
+
+	j, ok := v.Value.(*flags.JVMOptions)
+	if !ok {
+		return nil
+	}
+
+	o := flags.JVMOptions{}
+	if err := o.Set(value); err != nil {
+		return fmt.Errorf("unable to set --%s from %s: %w", flags.FlagJVMOptions, variable, err)
+	}
+
+	if err := o.Append(j.Tokens...); err != nil {
+		return err
+	}
+
+	*j = o
+	return nil
+}
+
+// ParseVCAPApplication returns the memory limit, limits.mem, of a Cloud Foundry VCAP_APPLICATION document.  The limit
+// is expressed in mebibytes.  0 is returned if the document has no memory limit.
+func ParseVCAPApplication(s string) (memory.Size, error) {
+	var v struct {
+		Limits struct {
+			Mem int64 `json:"mem"`
+		} `json:"limits"`
+	}
+
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return 0, fmt.Errorf("unable to parse %s: %w", VCAPApplication, err)
+	}
+
+	if v.Limits.Mem < 0 {
+		return 0, fmt.Errorf("%s memory limit must be positive: %d", VCAPApplication, v.Limits.Mem)
+	}
+
+	return memory.Size(v.Limits.Mem * memory.Mibi), nil
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package environment_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/environment"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/spf13/pflag"
+)
+
+func TestApply(t *testing.T) {
+	spec.Run(t, "Apply", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var (
+			f   *pflag.FlagSet
+			env map[string]string
+			h   flags.HeadRoom
+			j   flags.JVMOptions
+			l   flags.LoadedClassCount
+			tc  flags.ThreadCount
+			m   flags.TotalMemory
+		)
+
+		lookup := func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		}
+
+		it.Before(func() {
+			env = make(map[string]string)
+			h, j, l, tc, m = flags.DefaultHeadRoom, flags.JVMOptions{}, flags.DefaultLoadedClassCount, flags.DefaultThreadCount, flags.DefaultTotalMemory
+
+			f = pflag.NewFlagSet("test", pflag.ContinueOnError)
+			f.Var(&h, flags.FlagHeadRoom, "")
+			f.Var(&j, flags.FlagJVMOptions, "")
+			f.Var(&l, flags.FlagLoadedClassCount, "")
+			f.Var(&tc, flags.FlagThreadCount, "")
+			f.Var(&m, flags.FlagTotalMemory, "")
+		})
+
+		it("sets flags from BPL_JVM variables", func() {
+			env[environment.HeadRoom] = "5"
+			env[environment.LoadedClassCount] = "1000"
+			env[environment.ThreadCount] = "50"
+			env[environment.TotalMemory] = "1G"
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(h).To(Equal(flags.HeadRoom(5)))
+			g.Expect(l).To(Equal(flags.LoadedClassCount(1000)))
+			g.Expect(tc).To(Equal(flags.ThreadCount(50)))
+			g.Expect(m).To(Equal(flags.TotalMemory(memory.Gibi)))
+			g.Expect(f.Changed(flags.FlagTotalMemory)).To(BeTrue())
+		})
+
+		it("does not override flags", func() {
+			env[environment.ThreadCount] = "50"
+			g.Expect(f.Parse([]string{"--thread-count", "10"})).To(Succeed())
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(tc).To(Equal(flags.ThreadCount(10)))
+		})
+
+		it("ignores empty variables", func() {
+			env[environment.ThreadCount] = ""
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(f.Changed(flags.FlagThreadCount)).To(BeFalse())
+		})
+
+		it("prefers BPL_JVM_TOTAL_MEMORY to MEMORY_LIMIT and VCAP_APPLICATION", func() {
+			env[environment.TotalMemory] = "1G"
+			env[environment.MemoryLimit] = "512m"
+			env[environment.VCAPApplication] = `{"limits":{"mem":256}}`
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(m).To(Equal(flags.TotalMemory(memory.Gibi)))
+		})
+
+		it("prefers MEMORY_LIMIT to VCAP_APPLICATION", func() {
+			env[environment.MemoryLimit] = "512m"
+			env[environment.VCAPApplication] = `{"limits":{"mem":256}}`
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(m).To(Equal(flags.TotalMemory(512 * memory.Mibi)))
+		})
+
+		it("sets total memory from VCAP_APPLICATION", func() {
+			env[environment.VCAPApplication] = `{"application_name":"test","limits":{"disk":1024,"fds":16384,"mem":256}}`
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(m).To(Equal(flags.TotalMemory(256 * memory.Mibi)))
+		})
+
+		it("combines JAVA_TOOL_OPTIONS and JAVA_OPTS", func() {
+			env[environment.JavaToolOptions] = "-Xss256K -Xmx1G"
+			env[environment.JavaOpts] = "-Xmx2G"
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(*j.Stack).To(Equal(memory.Stack(256 * memory.Kibi)))
+			g.Expect(*j.MaxHeap).To(Equal(memory.MaxHeap(2 * memory.Gibi)))
+		})
+
+		it("puts JAVA_TOOL_OPTIONS in front of command line JVM options", func() {
+			env[environment.JavaToolOptions] = "-Xmx300m -javaagent:/a.jar"
+			env[environment.JavaOpts] = "-Xmx2G"
+			g.Expect(f.Parse([]string{"--jvm-options", "-Xss512k"})).To(Succeed())
+
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			g.Expect(j.Tokens).To(Equal([]string{"-Xmx300m", "-javaagent:/a.jar", "-Xss512k"}))
+			g.Expect(*j.MaxHeap).To(Equal(memory.MaxHeap(300 * memory.Mibi)))
+			g.Expect(*j.Stack).To(Equal(memory.Stack(512 * memory.Kibi)))
+		})
+
+		it("does not read JAVA_OPTS for exec.d", func() {
+			env[environment.JavaToolOptions] = "-Xss256K"
+			env[environment.JavaOpts] = "-Xmx2G"
+			env[environment.ThreadCount] = "50"
+
+			g.Expect(environment.ApplyExecD(f, lookup)).To(Succeed())
+
+			g.Expect(*j.Stack).To(Equal(memory.Stack(256 * memory.Kibi)))
+			g.Expect(j.MaxHeap).To(BeNil())
+			g.Expect(tc).To(Equal(flags.ThreadCount(50)))
+		})
+
+		it("returns error if variable is invalid", func() {
+			env[environment.ThreadCount] = "many"
+
+			g.Expect(environment.Apply(f, lookup)).To(MatchError(HavePrefix("unable to set --thread-count from BPL_JVM_THREAD_COUNT")))
+		})
+	})
+}
+
+func TestParseVCAPApplication(t *testing.T) {
+	spec.Run(t, "ParseVCAPApplication", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("parses memory limit in mebibytes", func() {
+			g.Expect(environment.ParseVCAPApplication(`{"limits":{"mem":1024}}`)).To(Equal(memory.Size(memory.Gibi)))
+		})
+
+		it("returns 0 without memory limit", func() {
+			g.Expect(environment.ParseVCAPApplication(`{}`)).To(Equal(memory.Size(0)))
+		})
+
+		it("returns error if document is invalid", func() {
+			_, err := environment.ParseVCAPApplication(`{`)
+			g.Expect(err).To(HaveOccurred())
+		})
+	})
+}
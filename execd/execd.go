@@ -0,0 +1,62 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package execd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/output"
+)
+
+const (
+	FlagExecD     = "exec-d"
+	FlagExecDPath = "exec-d-path"
+)
+
+// FileDescriptor is the file descriptor that Cloud Native Buildpacks read exec.d environment overrides from.
+const FileDescriptor = 3
+
+// IsHelper returns whether an executable is an exec.d helper: whether it is in an exec.d directory, either directly or
+// in that of a process type.
+func IsHelper(executable string) bool {
+	d := filepath.Dir(executable)
+	return filepath.Base(d) == "exec.d" || filepath.Base(filepath.Dir(d)) == "exec.d"
+}
+
+// Write writes JAVA_TOOL_OPTIONS as TOML, whose value is the existing options followed by JVM options, to path or, if
+// path is empty, to FileDescriptor.
+func Write(path string, options []string, existing string) error {
+	var w *os.File
+
+	if path == "" {
+		w = os.NewFile(FileDescriptor, fmt.Sprintf("/dev/fd/%d", FileDescriptor))
+	} else {
+		var err error
+		if w, err = os.Create(path); err != nil {
+			return fmt.Errorf("unable to create exec.d output: %w", err)
+		}
+	}
+
+	if err := output.TOML(w, options, existing); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("unable to write exec.d output: %w", err)
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package execd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/execd"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestIsHelper(t *testing.T) {
+	spec.Run(t, "IsHelper", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("detects exec.d directory", func() {
+			g.Expect(execd.IsHelper("/layers/paketo-buildpacks_bellsoft-liberica/helper/exec.d/memory-calculator")).To(BeTrue())
+		})
+
+		it("detects exec.d directory of a process type", func() {
+			g.Expect(execd.IsHelper("/layers/paketo-buildpacks_bellsoft-liberica/helper/exec.d/web/memory-calculator")).To(BeTrue())
+		})
+
+		it("does not detect other directories", func() {
+			g.Expect(execd.IsHelper("/usr/local/bin/java-buildpack-memory-calculator")).To(BeFalse())
+			g.Expect(execd.IsHelper("java-buildpack-memory-calculator")).To(BeFalse())
+			g.Expect(execd.IsHelper("/layers/exec.d/web/bin/memory-calculator")).To(BeFalse())
+		})
+	})
+}
+
+func TestWrite(t *testing.T) {
+	spec.Run(t, "Write", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var path string
+
+		it.Before(func() {
+			var err error
+			path, err = ioutil.TempDir("", "execd")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("writes TOML to path", func() {
+			file := filepath.Join(path, "env.toml")
+
+			g.Expect(execd.Write(file, []string{"-Xss1K", "-Xmx1M"}, "-Dfoo=bar")).To(Succeed())
+
+			b, err := ioutil.ReadFile(file)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(b)).To(Equal(`JAVA_TOOL_OPTIONS = "-Dfoo=bar -Xss1K -Xmx1M"` + "\n"))
+		})
+
+		it("returns error if path cannot be created", func() {
+			g.Expect(execd.Write(filepath.Join(path, "does-not-exist", "env.toml"), nil, "")).
+				To(MatchError(HavePrefix("unable to create exec.d output")))
+		})
+	})
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	DefaultApplicationPath = ApplicationPath("")
+	FlagApplicationPath    = "application-path"
+)
+
+type ApplicationPath string
+
+func (a *ApplicationPath) Set(s string) error {
+	*a = ApplicationPath(s)
+	return nil
+}
+
+func (a *ApplicationPath) String() string {
+	return string(*a)
+}
+
+func (a *ApplicationPath) Type() string {
+	return "string"
+}
+
+func (a *ApplicationPath) Validate() error {
+	if *a == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(string(*a)); err != nil {
+		return fmt.Errorf("--%s must exist: %s", FlagApplicationPath, *a)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestApplicationPath(t *testing.T) {
+	spec.Run(t, "ApplicationPath", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var path string
+
+		it.Before(func() {
+			var err error
+			path, err = ioutil.TempDir("", "application-path")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("is valid if not specified", func() {
+			a := flags.ApplicationPath("")
+
+			g.Expect(a.Validate()).To(Succeed())
+		})
+
+		it("is invalid if it does not exist", func() {
+			a := flags.ApplicationPath(filepath.Join(path, "does-not-exist"))
+
+			g.Expect(a.Validate()).NotTo(Succeed())
+		})
+
+		it("is valid if it exists", func() {
+			a := flags.ApplicationPath(path)
+
+			g.Expect(a.Validate()).To(Succeed())
+		})
+
+		it("parses value", func() {
+			var a flags.ApplicationPath
+
+			g.Expect(a.Set(path)).To(Succeed())
+			g.Expect(a).To(Equal(flags.ApplicationPath(path)))
+		})
+	})
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/application"
+)
+
+const (
+	DefaultClassLoadFactor = ClassLoadFactor(application.DefaultLoadFactor)
+	FlagClassLoadFactor    = "class-load-factor"
+)
+
+type ClassLoadFactor float64
+
+func (c *ClassLoadFactor) Set(s string) error {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	*c = ClassLoadFactor(f)
+	return nil
+}
+
+func (c *ClassLoadFactor) String() string {
+	return strconv.FormatFloat(float64(*c), 'f', -1, 64)
+}
+
+func (c *ClassLoadFactor) Type() string {
+	return "float64"
+}
+
+func (c *ClassLoadFactor) Validate() error {
+	if *c <= 0 || *c > 1 {
+		return fmt.Errorf("--%s must be greater than 0 and less than or equal to 1: %s", FlagClassLoadFactor, c)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	DefaultConfig = Config("")
+	FlagConfig    = "config"
+)
+
+type Config string
+
+func (c *Config) Set(s string) error {
+	*c = Config(s)
+	return nil
+}
+
+func (c *Config) String() string {
+	return string(*c)
+}
+
+func (c *Config) Type() string {
+	return "string"
+}
+
+func (c *Config) Validate() error {
+	if *c == "" {
+		return nil
+	}
+
+	if s, err := os.Stat(string(*c)); err != nil {
+		return fmt.Errorf("--%s must exist: %s", FlagConfig, *c)
+	} else if s.IsDir() {
+		return fmt.Errorf("--%s must be a file: %s", FlagConfig, *c)
+	}
+
+	return nil
+}
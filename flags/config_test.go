@@ -0,0 +1,81 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestConfig(t *testing.T) {
+	spec.Run(t, "Config", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var path string
+
+		it.Before(func() {
+			var err error
+			path, err = ioutil.TempDir("", "config")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("is valid if not specified", func() {
+			c := flags.Config("")
+
+			g.Expect(c.Validate()).To(Succeed())
+		})
+
+		it("is invalid if it does not exist", func() {
+			c := flags.Config(filepath.Join(path, "memory-calculator.yml"))
+
+			g.Expect(c.Validate()).To(MatchError(HavePrefix("--config must exist")))
+		})
+
+		it("is invalid if it is a directory", func() {
+			c := flags.Config(path)
+
+			g.Expect(c.Validate()).To(MatchError(HavePrefix("--config must be a file")))
+		})
+
+		it("is valid if it is a file", func() {
+			file := filepath.Join(path, "memory-calculator.yml")
+			g.Expect(ioutil.WriteFile(file, []byte("thread-count: 50\n"), 0644)).To(Succeed())
+
+			c := flags.Config(file)
+
+			g.Expect(c.Validate()).To(Succeed())
+		})
+
+		it("parses value", func() {
+			var c flags.Config
+
+			g.Expect(c.Set("memory-calculator.yml")).To(Succeed())
+			g.Expect(c).To(Equal(flags.Config("memory-calculator.yml")))
+		})
+	})
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	DefaultCPUCount = CPUCount(0)
+	FlagCPUCount    = "cpu-count"
+)
+
+type CPUCount int
+
+func (c *CPUCount) Set(s string) error {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*c = CPUCount(i)
+	return nil
+}
+
+func (c *CPUCount) String() string {
+	return strconv.FormatInt(int64(*c), 10)
+}
+
+func (c *CPUCount) Type() string {
+	return "int"
+}
+
+func (c *CPUCount) Validate() error {
+	if *c < 0 {
+		return fmt.Errorf("--%s must be positive: %d", FlagCPUCount, *c)
+	}
+
+	return nil
+}
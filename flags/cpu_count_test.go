@@ -0,0 +1,51 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestCPUCount(t *testing.T) {
+	spec.Run(t, "CPUCount", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("is invalid less than 0", func() {
+			c := flags.CPUCount(-1)
+
+			g.Expect(c.Validate()).NotTo(Succeed())
+		})
+
+		it("is valid at 0", func() {
+			c := flags.CPUCount(0)
+
+			g.Expect(c.Validate()).To(Succeed())
+		})
+
+		it("parses value", func() {
+			var c flags.CPUCount
+
+			g.Expect(c.Set("4")).To(Succeed())
+			g.Expect(c).To(Equal(flags.CPUCount(4)))
+		})
+	})
+}
@@ -19,6 +19,7 @@ package flags
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -26,10 +27,12 @@ const (
 	FlagHeadRoom    = "head-room"
 )
 
-type HeadRoom int
+// HeadRoom is the percentage of total memory left unallocated.
+type HeadRoom float64
 
+// Set parses a percentage, optionally suffixed with % (e.g. 5 or 2.5%).
 func (h *HeadRoom) Set(s string) error {
-	f, err := strconv.ParseInt(s, 10, 64)
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
 	if err != nil {
 		return err
 	}
@@ -39,17 +42,90 @@ func (h *HeadRoom) Set(s string) error {
 }
 
 func (h *HeadRoom) String() string {
-	return strconv.FormatInt(int64(*h), 10)
+	return strconv.FormatFloat(float64(*h), 'f', -1, 64)
 }
 
 func (h *HeadRoom) Type() string {
-	return "int"
+	return "float"
 }
 
 func (h *HeadRoom) Validate() error {
-	if *h < 0 || *h > 100 {
-		return fmt.Errorf("--%s must be a valid percentage: %d", FlagHeadRoom, *h)
+	if !(*h >= 0 && *h <= 100) {
+		return fmt.Errorf("--%s must be a valid percentage: %s", FlagHeadRoom, h)
 	}
 
 	return nil
 }
+
+// HeadRoomValue sets both a HeadRoom and a HeadRoomSize from a single --head-room flag, so that the memory left
+// unallocated can be a percentage of total memory, an absolute size, or the larger of both.
+type HeadRoomValue struct {
+	Percentage *HeadRoom
+	Size       *HeadRoomSize
+}
+
+// Set parses a percentage (e.g. 5 or 2.5%), a size with a size classification (e.g. 64M), or both separated by a
+// comma (e.g. 5%,64M).  Whichever is not specified is reset to 0.
+func (h HeadRoomValue) Set(s string) error {
+	var (
+		percentage      HeadRoom
+		size            HeadRoomSize
+		hasPct, hasSize bool
+	)
+
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+
+		if err := percentage.Set(p); err == nil {
+			if hasPct {
+				return fmt.Errorf("--%s must contain at most one percentage: %s", FlagHeadRoom, s)
+			}
+
+			hasPct = true
+			continue
+		}
+
+		if err := size.Set(p); err != nil {
+			return fmt.Errorf("--%s must be a percentage or a size: %s", FlagHeadRoom, p)
+		}
+
+		if hasSize {
+			return fmt.Errorf("--%s must contain at most one size: %s", FlagHeadRoom, s)
+		}
+
+		hasSize = true
+	}
+
+	*h.Percentage, *h.Size = percentage, size
+	return nil
+}
+
+func (h HeadRoomValue) String() string {
+	if h.Percentage == nil || h.Size == nil {
+		return ""
+	}
+
+	var values []string
+
+	if *h.Percentage != 0 || *h.Size == 0 {
+		values = append(values, h.Percentage.String()+"%")
+	}
+
+	if *h.Size != 0 {
+		values = append(values, h.Size.String())
+	}
+
+	return strings.Join(values, ",")
+}
+
+func (h HeadRoomValue) Type() string {
+	return "string"
+}
+
+func (h HeadRoomValue) Validate() error {
+	if err := h.Percentage.Validate(); err != nil {
+		return err
+	}
+
+	return h.Size.Validate()
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+const DefaultHeadRoomSize = HeadRoomSize(0)
+
+// HeadRoomSize is the absolute amount of memory left unallocated.  It is used instead of HeadRoom if it is larger.
+type HeadRoomSize memory.Size
+
+func (h *HeadRoomSize) Set(s string) error {
+	m, err := memory.ParseSize(s)
+	if err != nil {
+		return err
+	}
+
+	*h = HeadRoomSize(m)
+	return nil
+}
+
+func (h *HeadRoomSize) String() string {
+	return memory.Size(*h).String()
+}
+
+func (h *HeadRoomSize) Type() string {
+	return "int64"
+}
+
+func (h *HeadRoomSize) Validate() error {
+	if *h < 0 {
+		return fmt.Errorf("--%s must not be negative: %s", FlagHeadRoom, h)
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
 	. "github.com/onsi/gomega"
 	"github.com/sclevine/spec"
 )
@@ -54,5 +55,75 @@ func TestHeadRoom(t *testing.T) {
 			g.Expect(h).To(Equal(flags.HeadRoom(50)))
 		})
 
+		it("parses fractional percentage", func() {
+			var h flags.HeadRoom
+
+			g.Expect(h.Set("2.5%")).To(Succeed())
+			g.Expect(h).To(Equal(flags.HeadRoom(2.5)))
+			g.Expect(h.String()).To(Equal("2.5"))
+		})
+	})
+}
+
+func TestHeadRoomValue(t *testing.T) {
+	spec.Run(t, "HeadRoomValue", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var (
+			p flags.HeadRoom
+			s flags.HeadRoomSize
+			h flags.HeadRoomValue
+		)
+
+		it.Before(func() {
+			p, s = flags.DefaultHeadRoom, flags.DefaultHeadRoomSize
+			h = flags.HeadRoomValue{Percentage: &p, Size: &s}
+		})
+
+		it("parses percentage", func() {
+			g.Expect(h.Set("2.5%")).To(Succeed())
+			g.Expect(p).To(Equal(flags.HeadRoom(2.5)))
+			g.Expect(s).To(Equal(flags.HeadRoomSize(0)))
+			g.Expect(h.String()).To(Equal("2.5%"))
+		})
+
+		it("parses size", func() {
+			g.Expect(h.Set("64M")).To(Succeed())
+			g.Expect(p).To(Equal(flags.HeadRoom(0)))
+			g.Expect(s).To(Equal(flags.HeadRoomSize(64 * memory.Mibi)))
+			g.Expect(h.String()).To(Equal("64M"))
+		})
+
+		it("parses percentage and size", func() {
+			g.Expect(h.Set("5%,64M")).To(Succeed())
+			g.Expect(p).To(Equal(flags.HeadRoom(5)))
+			g.Expect(s).To(Equal(flags.HeadRoomSize(64 * memory.Mibi)))
+			g.Expect(h.String()).To(Equal("5%,64M"))
+		})
+
+		it("resets whichever is not specified", func() {
+			g.Expect(h.Set("5%,64M")).To(Succeed())
+			g.Expect(h.Set("10")).To(Succeed())
+			g.Expect(p).To(Equal(flags.HeadRoom(10)))
+			g.Expect(s).To(Equal(flags.HeadRoomSize(0)))
+		})
+
+		it("does not parse invalid values", func() {
+			g.Expect(h.Set("5%,10%")).NotTo(Succeed())
+			g.Expect(h.Set("64M,1G")).NotTo(Succeed())
+			g.Expect(h.Set("lots")).NotTo(Succeed())
+		})
+
+		it("validates percentage and size", func() {
+			p = 101
+			g.Expect(h.Validate()).NotTo(Succeed())
+
+			p, s = 5, -1
+			g.Expect(h.Validate()).NotTo(Succeed())
+
+			s = flags.HeadRoomSize(64 * memory.Mibi)
+			g.Expect(h.Validate()).To(Succeed())
+		})
 	})
 }
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+)
+
+const (
+	DefaultHeapMode = HeapModeSize
+	FlagHeapMode    = "heap-mode"
+)
+
+const (
+	HeapModePercentage = HeapMode("percentage")
+	HeapModeSize       = HeapMode("size")
+)
+
+var heapModes = []HeapMode{HeapModePercentage, HeapModeSize}
+
+// HeapMode is how the heap is expressed: as a size with -Xmx and -Xms, or as a percentage of total memory with
+// -XX:MaxRAMPercentage and -XX:InitialRAMPercentage.
+type HeapMode string
+
+func (h *HeapMode) Set(s string) error {
+	*h = HeapMode(s)
+	return nil
+}
+
+func (h *HeapMode) String() string {
+	return string(*h)
+}
+
+func (h *HeapMode) Type() string {
+	return "string"
+}
+
+func (h *HeapMode) Validate() error {
+	for _, v := range heapModes {
+		if *h == v {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("--%s must be one of %v: %s", FlagHeapMode, heapModes, *h)
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	DefaultInitialHeapPercentage = InitialHeapPercentage(0)
+	FlagInitialHeapPercentage    = "initial-heap-percentage"
+)
+
+type InitialHeapPercentage int
+
+func (i *InitialHeapPercentage) Set(s string) error {
+	f, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*i = InitialHeapPercentage(f)
+	return nil
+}
+
+func (i *InitialHeapPercentage) String() string {
+	return strconv.FormatInt(int64(*i), 10)
+}
+
+func (i *InitialHeapPercentage) Type() string {
+	return "int"
+}
+
+func (i *InitialHeapPercentage) Validate() error {
+	if *i < 0 || *i > 100 {
+		return fmt.Errorf("--%s must be a valid percentage: %d", FlagInitialHeapPercentage, *i)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	DefaultJavaHome = JavaHome("")
+	FlagJavaHome    = "java-home"
+)
+
+type JavaHome string
+
+func (j *JavaHome) Set(s string) error {
+	*j = JavaHome(s)
+	return nil
+}
+
+func (j *JavaHome) String() string {
+	return string(*j)
+}
+
+func (j *JavaHome) Type() string {
+	return "string"
+}
+
+func (j *JavaHome) Validate() error {
+	if *j == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(string(*j)); err != nil {
+		return fmt.Errorf("--%s must exist: %s", FlagJavaHome, *j)
+	}
+
+	return nil
+}
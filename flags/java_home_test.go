@@ -0,0 +1,72 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestJavaHome(t *testing.T) {
+	spec.Run(t, "JavaHome", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var path string
+
+		it.Before(func() {
+			var err error
+			path, err = ioutil.TempDir("", "java-home")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(path)).To(Succeed())
+		})
+
+		it("is valid if not specified", func() {
+			j := flags.JavaHome("")
+
+			g.Expect(j.Validate()).To(Succeed())
+		})
+
+		it("is invalid if it does not exist", func() {
+			j := flags.JavaHome(filepath.Join(path, "does-not-exist"))
+
+			g.Expect(j.Validate()).NotTo(Succeed())
+		})
+
+		it("is valid if it exists", func() {
+			j := flags.JavaHome(path)
+
+			g.Expect(j.Validate()).To(Succeed())
+		})
+
+		it("parses value", func() {
+			var j flags.JavaHome
+
+			g.Expect(j.Set(path)).To(Succeed())
+			g.Expect(j).To(Equal(flags.JavaHome(path)))
+		})
+	})
+}
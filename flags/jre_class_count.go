@@ -0,0 +1,57 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/application"
+)
+
+const (
+	DefaultJREClassCount = JREClassCount(application.DefaultJREClassCount)
+	FlagJREClassCount    = "jre-class-count"
+)
+
+type JREClassCount int
+
+func (j *JREClassCount) Set(s string) error {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*j = JREClassCount(i)
+	return nil
+}
+
+func (j *JREClassCount) String() string {
+	return strconv.FormatInt(int64(*j), 10)
+}
+
+func (j *JREClassCount) Type() string {
+	return "int"
+}
+
+func (j *JREClassCount) Validate() error {
+	if *j < 0 {
+		return fmt.Errorf("--%s must be positive: %d", FlagJREClassCount, *j)
+	}
+
+	return nil
+}
@@ -27,16 +27,115 @@ var DefaultJVMOptions = JVMOptions{}
 const FlagJVMOptions = "jvm-options"
 
 type JVMOptions struct {
-	MaxDirectMemory   *memory.MaxDirectMemory
-	MaxHeap           *memory.MaxHeap
-	MaxMetaspace      *memory.MaxMetaspace
-	ReservedCodeCache *memory.ReservedCodeCache
-	Stack             *memory.Stack
+	CodeCacheTotal          *memory.CodeCacheTotal
+	CompressedClassPointers *memory.CompressedClassPointers
+	CompressedClassSpace    *memory.CompressedClassSpace
+	GarbageCollector        *memory.GarbageCollector
+	InitialHeap             *memory.InitialHeap
+	InitialRAMPercentage    *memory.InitialRAMPercentage
+	MaxDirectMemory         *memory.MaxDirectMemory
+	MaxHeap                 *memory.MaxHeap
+	MaxMetaspace            *memory.MaxMetaspace
+	MaxNewSize              *memory.MaxNewSize
+	MaxPermSize             *memory.MaxPermSize
+	MaxRAM                  *memory.MaxRAM
+	MaxRAMPercentage        *memory.MaxRAMPercentage
+	MinRAMPercentage        *memory.MinRAMPercentage
+	NewRatio                *memory.NewRatio
+	NewSize                 *memory.NewSize
+	ReservedCodeCache       *memory.ReservedCodeCache
+	SharedClassCache        *memory.SharedClassCache
+	Stack                   *memory.Stack
+	TieredCompilation       *memory.TieredCompilation
+	YoungGeneration         *memory.YoungGeneration
+
+	// Tokens are the options as they were specified, including those that do not configure memory.
+	Tokens []string
+}
+
+// optionKeys identify the options that configure the same value, of which only the last is effective.
+var optionKeys = []struct {
+	key string
+	is  func(string) bool
+}{
+	{"CodeCacheTotal", memory.IsCodeCacheTotal},
+	{"CompressedClassPointers", memory.IsCompressedClassPointers},
+	{"CompressedClassSpace", memory.IsCompressedClassSpace},
+	{"GarbageCollector", memory.IsGarbageCollector},
+	{"InitialHeap", memory.IsInitialHeap},
+	{"InitialRAMPercentage", memory.IsInitialRAMPercentage},
+	{"MaxDirectMemory", memory.IsMaxDirectMemory},
+	{"MaxHeap", memory.IsMaxHeap},
+	{"MaxMetaspace", memory.IsMaxMetaspace},
+	{"MaxNewSize", memory.IsMaxNewSize},
+	{"MaxPermSize", memory.IsMaxPermSize},
+	{"MaxRAM", memory.IsMaxRAM},
+	{"MaxRAMPercentage", memory.IsMaxRAMPercentage},
+	{"MinRAMPercentage", memory.IsMinRAMPercentage},
+	{"NewRatio", memory.IsNewRatio},
+	{"NewSize", memory.IsNewSize},
+	{"ReservedCodeCache", memory.IsReservedCodeCache},
+	{"SharedClassCache", memory.IsSharedClassCache},
+	{"Stack", memory.IsStack},
+	{"TieredCompilation", memory.IsTieredCompilation},
+	{"YoungGeneration", memory.IsYoungGeneration},
 }
 
 func (j *JVMOptions) Set(s string) error {
-	for _, c := range strings.Split(s, " ") {
-		if memory.IsMaxDirectMemory(c) {
+	t, err := tokenize(s)
+	if err != nil {
+		return err
+	}
+
+	return j.Append(t...)
+}
+
+// Append parses options that have already been split into tokens and adds them to Tokens.
+func (j *JVMOptions) Append(tokens ...string) error {
+	for _, c := range tokens {
+		if memory.IsCodeCacheTotal(c) {
+			t, err := memory.ParseCodeCacheTotal(c)
+			if err != nil {
+				return err
+			}
+
+			j.CodeCacheTotal = &t
+		} else if memory.IsCompressedClassPointers(c) {
+			p, err := memory.ParseCompressedClassPointers(c)
+			if err != nil {
+				return err
+			}
+
+			j.CompressedClassPointers = &p
+		} else if memory.IsCompressedClassSpace(c) {
+			s, err := memory.ParseCompressedClassSpace(c)
+			if err != nil {
+				return err
+			}
+
+			j.CompressedClassSpace = &s
+		} else if memory.IsGarbageCollector(c) {
+			g, err := memory.ParseGarbageCollector(c)
+			if err != nil {
+				return err
+			}
+
+			j.GarbageCollector = &g
+		} else if memory.IsInitialHeap(c) {
+			i, err := memory.ParseInitialHeap(c)
+			if err != nil {
+				return err
+			}
+
+			j.InitialHeap = &i
+		} else if memory.IsInitialRAMPercentage(c) {
+			i, err := memory.ParseInitialRAMPercentage(c)
+			if err != nil {
+				return err
+			}
+
+			j.InitialRAMPercentage = &i
+		} else if memory.IsMaxDirectMemory(c) {
 			m, err := memory.ParseMaxDirectMemory(c)
 			if err != nil {
 				return err
@@ -57,6 +156,55 @@ func (j *JVMOptions) Set(s string) error {
 			}
 
 			j.MaxMetaspace = &m
+		} else if memory.IsMaxNewSize(c) {
+			m, err := memory.ParseMaxNewSize(c)
+			if err != nil {
+				return err
+			}
+
+			j.MaxNewSize = &m
+		} else if memory.IsMaxPermSize(c) {
+			m, err := memory.ParseMaxPermSize(c)
+			if err != nil {
+				return err
+			}
+
+			j.MaxPermSize = &m
+		} else if memory.IsMaxRAM(c) {
+			m, err := memory.ParseMaxRAM(c)
+			if err != nil {
+				return err
+			}
+
+			j.MaxRAM = &m
+		} else if memory.IsMaxRAMPercentage(c) {
+			m, err := memory.ParseMaxRAMPercentage(c)
+			if err != nil {
+				return err
+			}
+
+			j.MaxRAMPercentage = &m
+		} else if memory.IsMinRAMPercentage(c) {
+			m, err := memory.ParseMinRAMPercentage(c)
+			if err != nil {
+				return err
+			}
+
+			j.MinRAMPercentage = &m
+		} else if memory.IsNewRatio(c) {
+			n, err := memory.ParseNewRatio(c)
+			if err != nil {
+				return err
+			}
+
+			j.NewRatio = &n
+		} else if memory.IsNewSize(c) {
+			n, err := memory.ParseNewSize(c)
+			if err != nil {
+				return err
+			}
+
+			j.NewSize = &n
 		} else if memory.IsReservedCodeCache(c) {
 			r, err := memory.ParseReservedCodeCache(c)
 			if err != nil {
@@ -64,6 +212,13 @@ func (j *JVMOptions) Set(s string) error {
 			}
 
 			j.ReservedCodeCache = &r
+		} else if memory.IsSharedClassCache(c) {
+			s, err := memory.ParseSharedClassCache(c)
+			if err != nil {
+				return err
+			}
+
+			j.SharedClassCache = &s
 		} else if memory.IsStack(c) {
 			s, err := memory.ParseStack(c)
 			if err != nil {
@@ -71,15 +226,118 @@ func (j *JVMOptions) Set(s string) error {
 			}
 
 			j.Stack = &s
+		} else if memory.IsTieredCompilation(c) {
+			t, err := memory.ParseTieredCompilation(c)
+			if err != nil {
+				return err
+			}
+
+			j.TieredCompilation = &t
+		} else if memory.IsYoungGeneration(c) {
+			y, err := memory.ParseYoungGeneration(c)
+			if err != nil {
+				return err
+			}
+
+			j.YoungGeneration = &y
 		}
 	}
 
+	j.Tokens = append(j.Tokens, tokens...)
 	return nil
 }
 
+// EffectiveTokens returns Tokens, omitting those that are overridden by a later option configuring the same value:
+// memory options (e.g. -Xmx1G followed by -XX:MaxHeapSize=2G), system properties with the same name, -XX options with
+// the same name (e.g. -XX:+UseStringDeduplication followed by -XX:-UseStringDeduplication) and repeated agents, which
+// would otherwise be loaded twice.  Other options are returned verbatim as the JVM accepts many of them repeatedly.
+func (j *JVMOptions) EffectiveTokens() []string {
+	last := make(map[string]int)
+	for i, t := range j.Tokens {
+		if k := optionKey(t); k != "" {
+			last[k] = i
+		}
+	}
+
+	var tokens []string
+	for i, t := range j.Tokens {
+		if k := optionKey(t); k == "" || last[k] == i {
+			tokens = append(tokens, t)
+		}
+	}
+
+	return tokens
+}
+
+// Excluding returns EffectiveTokens, omitting those that are overridden by an option in other configuring the same
+// value.
+func (j *JVMOptions) Excluding(other *JVMOptions) []string {
+	overridden := make(map[string]bool)
+	if other != nil {
+		for _, t := range other.Tokens {
+			if k := optionKey(t); k != "" {
+				overridden[k] = true
+			}
+		}
+	}
+
+	var tokens []string
+	for _, t := range j.EffectiveTokens() {
+		if k := optionKey(t); k == "" || !overridden[k] {
+			tokens = append(tokens, t)
+		}
+	}
+
+	return tokens
+}
+
+// optionKey returns the value that an option configures, or an empty string if the option may be repeated.
+func optionKey(token string) string {
+	for _, o := range optionKeys {
+		if o.is(token) {
+			return o.key
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(token, "-D"):
+		return "-D" + strings.SplitN(strings.TrimPrefix(token, "-D"), "=", 2)[0]
+	case strings.HasPrefix(token, "-XX:"):
+		return "-XX:" + strings.SplitN(strings.TrimLeft(strings.TrimPrefix(token, "-XX:"), "+-"), "=", 2)[0]
+	case strings.HasPrefix(token, "-javaagent:"), strings.HasPrefix(token, "-agentlib:"), strings.HasPrefix(token, "-agentpath:"):
+		return token
+	}
+
+	return ""
+}
+
 func (j *JVMOptions) String() string {
 	var values []string
 
+	if j.CodeCacheTotal != nil {
+		values = append(values, j.CodeCacheTotal.String())
+	}
+
+	if j.CompressedClassPointers != nil {
+		values = append(values, j.CompressedClassPointers.String())
+	}
+
+	if j.CompressedClassSpace != nil {
+		values = append(values, j.CompressedClassSpace.String())
+	}
+
+	if j.GarbageCollector != nil {
+		values = append(values, j.GarbageCollector.String())
+	}
+
+	if j.InitialHeap != nil {
+		values = append(values, j.InitialHeap.String())
+	}
+
+	if j.InitialRAMPercentage != nil {
+		values = append(values, j.InitialRAMPercentage.String())
+	}
+
 	if j.MaxDirectMemory != nil {
 		values = append(values, j.MaxDirectMemory.String())
 	}
@@ -92,14 +350,54 @@ func (j *JVMOptions) String() string {
 		values = append(values, j.MaxMetaspace.String())
 	}
 
+	if j.MaxNewSize != nil {
+		values = append(values, j.MaxNewSize.String())
+	}
+
+	if j.MaxPermSize != nil {
+		values = append(values, j.MaxPermSize.String())
+	}
+
+	if j.MaxRAM != nil {
+		values = append(values, j.MaxRAM.String())
+	}
+
+	if j.MaxRAMPercentage != nil {
+		values = append(values, j.MaxRAMPercentage.String())
+	}
+
+	if j.MinRAMPercentage != nil {
+		values = append(values, j.MinRAMPercentage.String())
+	}
+
+	if j.NewRatio != nil {
+		values = append(values, j.NewRatio.String())
+	}
+
+	if j.NewSize != nil {
+		values = append(values, j.NewSize.String())
+	}
+
 	if j.ReservedCodeCache != nil {
 		values = append(values, j.ReservedCodeCache.String())
 	}
 
+	if j.SharedClassCache != nil {
+		values = append(values, j.SharedClassCache.String())
+	}
+
 	if j.Stack != nil {
 		values = append(values, j.Stack.String())
 	}
 
+	if j.TieredCompilation != nil {
+		values = append(values, j.TieredCompilation.String())
+	}
+
+	if j.YoungGeneration != nil {
+		values = append(values, j.YoungGeneration.String())
+	}
+
 	return strings.Join(values, " ")
 }
 
@@ -55,7 +55,8 @@ func TestJVMOptions(t *testing.T) {
 			r := memory.ReservedCodeCache(memory.Kibi)
 			s := memory.Stack(memory.Kibi)
 
-			e := flags.JVMOptions{MaxDirectMemory: &d, MaxHeap: &h, MaxMetaspace: &m, ReservedCodeCache: &r, Stack: &s}
+			e := flags.JVMOptions{MaxDirectMemory: &d, MaxHeap: &h, MaxMetaspace: &m, ReservedCodeCache: &r, Stack: &s,
+				Tokens: []string{"-XX:MaxDirectMemorySize=1K", "-Xmx1K", "-XX:MaxMetaspaceSize=1K", "-XX:ReservedCodeCacheSize=1K", "-Xss1K"}}
 
 			var j flags.JVMOptions
 
@@ -63,5 +64,167 @@ func TestJVMOptions(t *testing.T) {
 			g.Expect(j).To(Equal(e))
 		})
 
+		it("parses initial heap", func() {
+			i := memory.InitialHeap(memory.Mibi)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-Xms1M")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{InitialHeap: &i, Tokens: []string{"-Xms1M"}}))
+			g.Expect(j.String()).To(Equal("-Xms1M"))
+		})
+
+		it("parses compressed class space", func() {
+			p := memory.CompressedClassPointers(false)
+			s := memory.CompressedClassSpace(memory.Mibi)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-XX:-UseCompressedClassPointers -XX:CompressedClassSpaceSize=1M")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{CompressedClassPointers: &p, CompressedClassSpace: &s,
+				Tokens: []string{"-XX:-UseCompressedClassPointers", "-XX:CompressedClassSpaceSize=1M"}}))
+			g.Expect(j.String()).To(Equal("-XX:-UseCompressedClassPointers -XX:CompressedClassSpaceSize=1M"))
+		})
+
+		it("parses young generation", func() {
+			m := memory.MaxNewSize(2 * memory.Mibi)
+			r := memory.NewRatio(3)
+			n := memory.NewSize(memory.Mibi)
+			y := memory.YoungGeneration(memory.Mibi)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-Xmn1M -XX:NewSize=1M -XX:MaxNewSize=2M -XX:NewRatio=3")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{MaxNewSize: &m, NewRatio: &r, NewSize: &n, YoungGeneration: &y,
+				Tokens: []string{"-Xmn1M", "-XX:NewSize=1M", "-XX:MaxNewSize=2M", "-XX:NewRatio=3"}}))
+			g.Expect(j.String()).To(Equal("-XX:MaxNewSize=2M -XX:NewRatio=3 -XX:NewSize=1M -Xmn1M"))
+		})
+
+		it("parses Java 7 and JIT options", func() {
+			p := memory.MaxPermSize(64 * memory.Mibi)
+			t := memory.TieredCompilation(false)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-XX:MaxPermSize=64M -XX:-TieredCompilation")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{MaxPermSize: &p, TieredCompilation: &t,
+				Tokens: []string{"-XX:MaxPermSize=64M", "-XX:-TieredCompilation"}}))
+			g.Expect(j.String()).To(Equal("-XX:MaxPermSize=64M -XX:-TieredCompilation"))
+		})
+
+		it("parses OpenJ9 options", func() {
+			c := memory.CodeCacheTotal(128 * memory.Mibi)
+			s := memory.SharedClassCache(64 * memory.Mibi)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-Xshareclasses -Xscmx64M -Xcodecachetotal128M")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{CodeCacheTotal: &c, SharedClassCache: &s,
+				Tokens: []string{"-Xshareclasses", "-Xscmx64M", "-Xcodecachetotal128M"}}))
+			g.Expect(j.String()).To(Equal("-Xcodecachetotal128M -Xscmx64M"))
+		})
+
+		it("parses garbage collector", func() {
+			c := memory.GarbageCollectorG1
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-XX:+UseParallelGC -XX:+UseG1GC")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{GarbageCollector: &c, Tokens: []string{"-XX:+UseParallelGC", "-XX:+UseG1GC"}}))
+			g.Expect(j.String()).To(Equal("-XX:+UseG1GC"))
+		})
+
+		it("parses RAM options", func() {
+			i := memory.InitialRAMPercentage(10)
+			m := memory.MaxRAM(2 * memory.Gibi)
+			x := memory.MaxRAMPercentage(75)
+			n := memory.MinRAMPercentage(50)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-XX:MaxRAM=2G -XX:MaxRAMPercentage=75 -XX:MinRAMPercentage=50.0 -XX:InitialRAMPercentage=10")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{InitialRAMPercentage: &i, MaxRAM: &m, MaxRAMPercentage: &x, MinRAMPercentage: &n,
+				Tokens: []string{"-XX:MaxRAM=2G", "-XX:MaxRAMPercentage=75", "-XX:MinRAMPercentage=50.0", "-XX:InitialRAMPercentage=10"}}))
+			g.Expect(j.String()).To(Equal("-XX:InitialRAMPercentage=10.00 -XX:MaxRAM=2G -XX:MaxRAMPercentage=75.00 -XX:MinRAMPercentage=50.00"))
+		})
+
+		it("parses aliases", func() {
+			h := memory.MaxHeap(512 * memory.Mibi)
+			r := memory.ReservedCodeCache(memory.Mibi)
+			s := memory.Stack(512 * memory.Kibi)
+
+			e := flags.JVMOptions{MaxHeap: &h, ReservedCodeCache: &r, Stack: &s,
+				Tokens: []string{"-XX:MaxHeapSize=512m", "-Xmaxjitcodesize1m", "-XX:ThreadStackSize=512"}}
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-XX:MaxHeapSize=512m -Xmaxjitcodesize1m -XX:ThreadStackSize=512")).To(Succeed())
+			g.Expect(j).To(Equal(e))
+		})
+
+		it("parses value separated by arbitrary whitespace", func() {
+			h := memory.MaxHeap(memory.Kibi)
+			s := memory.Stack(memory.Kibi)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("\t-Xmx1K  \n\t-Xss1K\r\n")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{MaxHeap: &h, Stack: &s, Tokens: []string{"-Xmx1K", "-Xss1K"}}))
+		})
+
+		it("parses value with quoted arguments", func() {
+			h := memory.MaxHeap(memory.Gibi)
+			s := memory.Stack(memory.Kibi)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set(`-Dfoo="a b -Xss2K" -Xmx1G '-Dbar=c -Xmx2G' -Dbaz=d\ -Xmx3G "-Xss1K"`)).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{MaxHeap: &h, Stack: &s,
+				Tokens: []string{"-Dfoo=a b -Xss2K", "-Xmx1G", "-Dbar=c -Xmx2G", "-Dbaz=d -Xmx3G", "-Xss1K"}}))
+		})
+
+		it("parses value with escaped arguments", func() {
+			h := memory.MaxHeap(memory.Gibi)
+			s := memory.Stack(memory.Kibi)
+
+			var j flags.JVMOptions
+
+			g.Expect(j.Set(`-Dfoo="a \"b\" \c" \-Xss1K -Dbar='\' \
+-Xmx1G`)).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMOptions{MaxHeap: &h, Stack: &s,
+				Tokens: []string{`-Dfoo=a "b" \c`, "-Xss1K", `-Dbar=\`, "-Xmx1G"}}))
+		})
+
+		it("returns effective tokens", func() {
+			var j flags.JVMOptions
+
+			g.Expect(j.Set("-Xmx1G -javaagent:a.jar -Xss256K -XX:+UseParallelGC -Dfoo=1 -XX:+UseStringDeduplication --add-opens java.base/java.lang=ALL-UNNAMED")).To(Succeed())
+			g.Expect(j.Set("-XX:MaxHeapSize=2G -javaagent:a.jar -XX:+UseG1GC -Dfoo=2 -XX:-UseStringDeduplication -javaagent:b.jar --add-opens java.base/java.io=ALL-UNNAMED")).To(Succeed())
+			g.Expect(j.EffectiveTokens()).To(Equal([]string{"-Xss256K", "--add-opens", "java.base/java.lang=ALL-UNNAMED",
+				"-XX:MaxHeapSize=2G", "-javaagent:a.jar", "-XX:+UseG1GC", "-Dfoo=2", "-XX:-UseStringDeduplication", "-javaagent:b.jar",
+				"--add-opens", "java.base/java.io=ALL-UNNAMED"}))
+		})
+
+		it("returns tokens excluding those overridden", func() {
+			var j, o flags.JVMOptions
+
+			g.Expect(j.Set("-Xmx1G -javaagent:a.jar -XX:ReservedCodeCacheSize=64M -Xss256K")).To(Succeed())
+			g.Expect(o.Set("-XX:MaxHeapSize=2G -Xss512K")).To(Succeed())
+			g.Expect(j.Excluding(&o)).To(Equal([]string{"-javaagent:a.jar", "-XX:ReservedCodeCacheSize=64M"}))
+			g.Expect(j.Excluding(nil)).To(Equal(j.EffectiveTokens()))
+
+			g.Expect(o.Set("-Dgreeting=hi -javaagent:a.jar")).To(Succeed())
+			g.Expect(j.Set("-Dgreeting=hello -javaagent:b.jar")).To(Succeed())
+			g.Expect(j.Excluding(&o)).To(Equal([]string{"-XX:ReservedCodeCacheSize=64M", "-javaagent:b.jar"}))
+		})
+
+		it("does not parse unterminated quotes", func() {
+			var j flags.JVMOptions
+
+			g.Expect(j.Set(`-Dfoo="a b -Xmx1G`)).NotTo(Succeed())
+			g.Expect(j.Set(`-Dfoo='a b -Xmx1G`)).NotTo(Succeed())
+			g.Expect(j.Set(`-Xmx1G \`)).NotTo(Succeed())
+		})
+
 	})
 }
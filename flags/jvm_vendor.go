@@ -0,0 +1,61 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+)
+
+const (
+	DefaultJVMVendor = JVMVendor("")
+	FlagJVMVendor    = "jvm-vendor"
+)
+
+var vendors = []JVMVendor{JVMVendor(jvm.VendorGraalVM), JVMVendor(jvm.VendorHotSpot), JVMVendor(jvm.VendorOpenJ9)}
+
+// JVMVendor is the virtual machine implementation of the JVM.  An empty vendor is unknown, in which case it is
+// detected from the release file or HotSpot is assumed.
+type JVMVendor string
+
+func (j *JVMVendor) Set(s string) error {
+	*j = JVMVendor(s)
+	return nil
+}
+
+func (j *JVMVendor) String() string {
+	return string(*j)
+}
+
+func (j *JVMVendor) Type() string {
+	return "string"
+}
+
+func (j *JVMVendor) Validate() error {
+	if *j == "" {
+		return nil
+	}
+
+	for _, v := range vendors {
+		if *j == v {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("--%s must be one of %v: %s", FlagJVMVendor, vendors, *j)
+}
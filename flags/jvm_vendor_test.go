@@ -0,0 +1,57 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestJVMVendor(t *testing.T) {
+	spec.Run(t, "JVMVendor", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("is valid if not specified", func() {
+			j := flags.DefaultJVMVendor
+
+			g.Expect(j.Validate()).To(Succeed())
+		})
+
+		it("is invalid if unknown", func() {
+			j := flags.JVMVendor("zing")
+
+			g.Expect(j.Validate()).NotTo(Succeed())
+		})
+
+		it("is valid if known", func() {
+			j := flags.JVMVendor("openj9")
+
+			g.Expect(j.Validate()).To(Succeed())
+		})
+
+		it("parses value", func() {
+			var j flags.JVMVendor
+
+			g.Expect(j.Set("openj9")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMVendor("openj9")))
+		})
+	})
+}
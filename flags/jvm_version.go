@@ -0,0 +1,58 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+)
+
+const (
+	DefaultJVMVersion = JVMVersion(0)
+	FlagJVMVersion    = "jvm-version"
+)
+
+// JVMVersion is the major version of the JVM.  0 is unknown, in which case the defaults of current JVMs are used.
+type JVMVersion int
+
+func (j *JVMVersion) Set(s string) error {
+	v, err := jvm.ParseVersion(s)
+	if err != nil {
+		return err
+	}
+
+	*j = JVMVersion(v)
+	return nil
+}
+
+func (j *JVMVersion) String() string {
+	return strconv.FormatInt(int64(*j), 10)
+}
+
+func (j *JVMVersion) Type() string {
+	return "string"
+}
+
+func (j *JVMVersion) Validate() error {
+	if *j != 0 && *j < 7 {
+		return fmt.Errorf("--%s must be 7 or later: %d", FlagJVMVersion, *j)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestJVMVersion(t *testing.T) {
+	spec.Run(t, "JVMVersion", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("is valid if not specified", func() {
+			j := flags.DefaultJVMVersion
+
+			g.Expect(j.Validate()).To(Succeed())
+		})
+
+		it("is invalid if earlier than 7", func() {
+			j := flags.JVMVersion(6)
+
+			g.Expect(j.Validate()).NotTo(Succeed())
+		})
+
+		it("is valid if 7 or later", func() {
+			j := flags.JVMVersion(7)
+
+			g.Expect(j.Validate()).To(Succeed())
+		})
+
+		it("parses major version", func() {
+			var j flags.JVMVersion
+
+			g.Expect(j.Set("11")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMVersion(11)))
+		})
+
+		it("parses full version", func() {
+			var j flags.JVMVersion
+
+			g.Expect(j.Set("1.8.0_252")).To(Succeed())
+			g.Expect(j).To(Equal(flags.JVMVersion(8)))
+		})
+
+		it("does not parse invalid version", func() {
+			var j flags.JVMVersion
+
+			g.Expect(j.Set("latest")).NotTo(Succeed())
+		})
+	})
+}
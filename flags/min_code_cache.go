@@ -0,0 +1,58 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+const (
+	DefaultMinCodeCache = MinCodeCache(32 * memory.Mibi)
+	FlagMinCodeCache    = "min-code-cache"
+)
+
+// MinCodeCache is the size below which the reserved code cache, or code cache total for OpenJ9, is not reduced to fit
+// MinHeap.
+type MinCodeCache memory.Size
+
+func (m *MinCodeCache) Set(s string) error {
+	z, err := memory.ParseSize(s)
+	if err != nil {
+		return err
+	}
+
+	*m = MinCodeCache(z)
+	return nil
+}
+
+func (m *MinCodeCache) String() string {
+	return memory.Size(*m).String()
+}
+
+func (m *MinCodeCache) Type() string {
+	return "int64"
+}
+
+func (m *MinCodeCache) Validate() error {
+	if *m < 0 {
+		return fmt.Errorf("--%s must not be negative: %d", FlagMinCodeCache, *m)
+	}
+
+	return nil
+}
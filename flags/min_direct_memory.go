@@ -0,0 +1,57 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+const (
+	DefaultMinDirectMemory = MinDirectMemory(memory.Mibi)
+	FlagMinDirectMemory    = "min-direct-memory"
+)
+
+// MinDirectMemory is the size below which direct memory is not reduced to fit MinHeap.
+type MinDirectMemory memory.Size
+
+func (m *MinDirectMemory) Set(s string) error {
+	z, err := memory.ParseSize(s)
+	if err != nil {
+		return err
+	}
+
+	*m = MinDirectMemory(z)
+	return nil
+}
+
+func (m *MinDirectMemory) String() string {
+	return memory.Size(*m).String()
+}
+
+func (m *MinDirectMemory) Type() string {
+	return "int64"
+}
+
+func (m *MinDirectMemory) Validate() error {
+	if *m < 0 {
+		return fmt.Errorf("--%s must not be negative: %d", FlagMinDirectMemory, *m)
+	}
+
+	return nil
+}
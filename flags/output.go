@@ -0,0 +1,61 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+)
+
+const (
+	DefaultOutput = OutputFlags
+	FlagOutput    = "output"
+)
+
+const (
+	OutputArgFile = Output("argfile")
+	OutputEnv     = Output("env")
+	OutputFlags   = Output("flags")
+	OutputJSON    = Output("json")
+	OutputShell   = Output("shell")
+)
+
+var outputs = []Output{OutputFlags, OutputJSON, OutputEnv, OutputShell, OutputArgFile}
+
+type Output string
+
+func (o *Output) Set(s string) error {
+	*o = Output(s)
+	return nil
+}
+
+func (o *Output) String() string {
+	return string(*o)
+}
+
+func (o *Output) Type() string {
+	return "string"
+}
+
+func (o *Output) Validate() error {
+	for _, v := range outputs {
+		if *o == v {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("--%s must be one of %v: %s", FlagOutput, outputs, *o)
+}
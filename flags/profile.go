@@ -0,0 +1,41 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+const (
+	DefaultProfile = Profile("")
+	FlagProfile    = "profile"
+)
+
+type Profile string
+
+func (p *Profile) Set(s string) error {
+	*p = Profile(s)
+	return nil
+}
+
+func (p *Profile) String() string {
+	return string(*p)
+}
+
+func (p *Profile) Type() string {
+	return "string"
+}
+
+func (p *Profile) Validate() error {
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flags
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenize splits a string into arguments following POSIX shell rules: arguments are separated by any amount of
+// whitespace, single quotes preserve every character literally, double quotes preserve every character except for
+// backslash escapes of '"', '\', '$' and '`', and a backslash outside of quotes escapes any character.  A backslash
+// followed by a newline is a line continuation outside of single quotes.
+func tokenize(s string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		inToken bool
+	)
+
+	r := []rune(s)
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+
+		switch {
+		case unicode.IsSpace(c):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+
+		case c == '\\':
+			if i+1 >= len(r) {
+				return nil, fmt.Errorf("unterminated escape: %s", s)
+			}
+
+			i++
+			if r[i] != '\n' {
+				current.WriteRune(r[i])
+				inToken = true
+			}
+
+		case c == '\'':
+			closed := false
+
+			for i++; i < len(r); i++ {
+				if r[i] == '\'' {
+					closed = true
+					break
+				}
+
+				current.WriteRune(r[i])
+			}
+
+			if !closed {
+				return nil, fmt.Errorf("unterminated single quote: %s", s)
+			}
+
+			inToken = true
+
+		case c == '"':
+			closed := false
+
+			for i++; i < len(r); i++ {
+				if r[i] == '"' {
+					closed = true
+					break
+				}
+
+				if r[i] == '\\' && i+1 < len(r) && strings.ContainsRune("\"\\$`\n", r[i+1]) {
+					i++
+					if r[i] != '\n' {
+						current.WriteRune(r[i])
+					}
+					continue
+				}
+
+				current.WriteRune(r[i])
+			}
+
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote: %s", s)
+			}
+
+			inToken = true
+
+		default:
+			current.WriteRune(c)
+			inToken = true
+		}
+	}
+
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
@@ -6,4 +6,5 @@ require (
 	github.com/onsi/gomega v1.10.1
 	github.com/sclevine/spec v1.4.0
 	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v2 v2.3.0
 )
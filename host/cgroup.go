@@ -0,0 +1,171 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type cgroup struct {
+	controllers []string
+	path        string
+}
+
+type cgroupMount struct {
+	root         string
+	mountPoint   string
+	fsType       string
+	superOptions []string
+}
+
+// cgroupDirectories returns the directories, from the process's own cgroup up to the root of the hierarchy, that
+// contain the files for a controller.  The boolean return value indicates whether the directories belong to a cgroup
+// v2 (unified) hierarchy.  No directories are returned if the controller is not mounted.
+func (h Host) cgroupDirectories(controller string) ([]string, bool, error) {
+	cgroups, err := h.cgroups()
+	if err != nil {
+		return nil, false, err
+	}
+
+	mounts, err := h.cgroupMounts()
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, c := range cgroups {
+		if !contains(c.controllers, controller) {
+			continue
+		}
+
+		for _, m := range mounts {
+			if m.fsType == "cgroup" && contains(m.superOptions, controller) {
+				return directories(m, c.path), false, nil
+			}
+		}
+	}
+
+	for _, c := range cgroups {
+		if len(c.controllers) != 0 {
+			continue
+		}
+
+		for _, m := range mounts {
+			if m.fsType == "cgroup2" {
+				return directories(m, c.path), true, nil
+			}
+		}
+	}
+
+	return nil, false, nil
+}
+
+func (h Host) cgroups() ([]cgroup, error) {
+	s, ok, err := h.readFile("proc", "self", "cgroup")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var cgroups []cgroup
+	for _, l := range strings.Split(s, "\n") {
+		if l == "" {
+			continue
+		}
+
+		f := strings.SplitN(l, ":", 3)
+		if len(f) != 3 {
+			return nil, fmt.Errorf("cgroup entry does not match pattern 'hierarchy-ID:controller-list:cgroup-path': %s", l)
+		}
+
+		c := cgroup{path: f[2]}
+		if f[1] != "" {
+			c.controllers = strings.Split(f[1], ",")
+		}
+
+		cgroups = append(cgroups, c)
+	}
+
+	return cgroups, nil
+}
+
+func (h Host) cgroupMounts() ([]cgroupMount, error) {
+	s, ok, err := h.readFile("proc", "self", "mountinfo")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var mounts []cgroupMount
+	for _, l := range strings.Split(s, "\n") {
+		f := strings.Fields(l)
+
+		separator := -1
+		for i, v := range f {
+			if v == "-" {
+				separator = i
+				break
+			}
+		}
+
+		if separator < 5 || len(f) < separator+4 {
+			continue
+		}
+
+		if f[separator+1] != "cgroup" && f[separator+1] != "cgroup2" {
+			continue
+		}
+
+		mounts = append(mounts, cgroupMount{
+			root:         f[3],
+			mountPoint:   f[4],
+			fsType:       f[separator+1],
+			superOptions: strings.Split(f[separator+3], ","),
+		})
+	}
+
+	return mounts, nil
+}
+
+func directories(m cgroupMount, cgroupPath string) []string {
+	relative := "/"
+	if m.root == "/" {
+		relative = cgroupPath
+	} else if cgroupPath == m.root || strings.HasPrefix(cgroupPath, m.root+"/") {
+		relative = strings.TrimPrefix(cgroupPath, m.root)
+	}
+
+	var d []string
+	for r := path.Clean("/" + relative); ; r = path.Dir(r) {
+		d = append(d, path.Join(m.mountPoint, r))
+
+		if r == "/" {
+			break
+		}
+	}
+
+	return d
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CPUCount returns the CPU quota of the process's cgroup, rounded up to whole CPUs, or 0 if the cgroup has no CPU
+// quota.  The number of CPUs of the host is deliberately not used instead, as it is typically far larger than the
+// share of an unlimited container, e.g. on Cloud Foundry.
+func (h Host) CPUCount() (int, error) {
+	q, _, err := h.cgroupCPUQuota()
+	return q, err
+}
+
+func (h Host) cgroupCPUQuota() (int, bool, error) {
+	d, v2, err := h.cgroupDirectories("cpu")
+	if err != nil {
+		return 0, false, err
+	}
+
+	quota, found := 0, false
+	for _, dir := range d {
+		var (
+			q, p int64
+			ok   bool
+		)
+
+		if v2 {
+			q, p, ok, err = h.cpuMax(dir)
+		} else {
+			q, p, ok, err = h.cfsQuota(dir)
+		}
+
+		if err != nil {
+			return 0, false, err
+		}
+
+		if !ok || q <= 0 || p <= 0 {
+			continue
+		}
+
+		if n := int((q + p - 1) / p); !found || n < quota {
+			quota, found = n, true
+		}
+	}
+
+	return quota, found, nil
+}
+
+func (h Host) cpuMax(dir string) (int64, int64, bool, error) {
+	s, ok, err := h.readFile(dir, "cpu.max")
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+
+	f := strings.Fields(s)
+	if len(f) != 2 {
+		return 0, 0, false, fmt.Errorf("cpu.max in %s does not match pattern 'quota period': %s", h.path(dir), s)
+	}
+
+	if f[0] == "max" {
+		return 0, 0, false, nil
+	}
+
+	q, err := strconv.ParseInt(f[0], 10, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("CPU quota in %s is not an integer: %s", h.path(dir, "cpu.max"), f[0])
+	}
+
+	p, err := strconv.ParseInt(f[1], 10, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("CPU period in %s is not an integer: %s", h.path(dir, "cpu.max"), f[1])
+	}
+
+	return q, p, true, nil
+}
+
+func (h Host) cfsQuota(dir string) (int64, int64, bool, error) {
+	q, ok, err := h.readInt(dir, "cpu.cfs_quota_us")
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+
+	p, ok, err := h.readInt(dir, "cpu.cfs_period_us")
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+
+	return q, p, true, nil
+}
+
+func (h Host) readInt(elem ...string) (int64, bool, error) {
+	s, ok, err := h.readFile(elem...)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s is not an integer: %s", h.path(elem...), s)
+	}
+
+	return i, true, nil
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/host"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestCPUCount(t *testing.T) {
+	spec.Run(t, "CPUCount", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var h host.Host
+
+		write := func(path string, content string) {
+			f := filepath.Join(h.Root, path)
+			g.Expect(os.MkdirAll(filepath.Dir(f), 0755)).To(Succeed())
+			g.Expect(ioutil.WriteFile(f, []byte(content), 0644)).To(Succeed())
+		}
+
+		it.Before(func() {
+			var err error
+			h.Root, err = ioutil.TempDir("", "host")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(h.Root)).To(Succeed())
+		})
+
+		when("cgroup v2", func() {
+
+			it.Before(func() {
+				write("proc/self/cgroup", "0::/system.slice/app.service\n")
+				write("proc/self/mountinfo", "30 23 0:26 / /sys/fs/cgroup rw,nosuid,nodev,noexec,relatime shared:4 - cgroup2 cgroup2 rw,nsdelegate\n")
+			})
+
+			it("reads cpu.max", func() {
+				write("sys/fs/cgroup/system.slice/app.service/cpu.max", "200000 100000\n")
+
+				g.Expect(h.CPUCount()).To(Equal(2))
+			})
+
+			it("rounds up fractional quota", func() {
+				write("sys/fs/cgroup/system.slice/app.service/cpu.max", "150000 100000\n")
+
+				g.Expect(h.CPUCount()).To(Equal(2))
+			})
+
+			it("uses the smallest quota of the hierarchy", func() {
+				write("sys/fs/cgroup/system.slice/app.service/cpu.max", "max 100000\n")
+				write("sys/fs/cgroup/system.slice/cpu.max", "100000 100000\n")
+
+				g.Expect(h.CPUCount()).To(Equal(1))
+			})
+
+			it("returns 0 if unlimited", func() {
+				write("sys/fs/cgroup/system.slice/app.service/cpu.max", "max 100000\n")
+
+				g.Expect(h.CPUCount()).To(Equal(0))
+			})
+
+			it("returns error if quota is malformed", func() {
+				write("sys/fs/cgroup/system.slice/app.service/cpu.max", "unknown\n")
+
+				_, err := h.CPUCount()
+				g.Expect(err).To(HaveOccurred())
+			})
+		})
+
+		when("cgroup v1", func() {
+
+			it.Before(func() {
+				write("proc/self/cgroup", "12:cpu,cpuacct:/docker/abc\n11:memory:/docker/abc\n1:name=systemd:/docker/abc\n")
+				write("proc/self/mountinfo", "25 24 0:22 / /sys/fs/cgroup ro,nosuid,nodev,noexec - tmpfs tmpfs ro,mode=755\n"+
+					"26 25 0:23 /docker/abc /sys/fs/cgroup/cpu,cpuacct ro,nosuid,nodev,noexec,relatime master:8 - cgroup cgroup rw,cpu,cpuacct\n"+
+					"27 25 0:24 /docker/abc /sys/fs/cgroup/memory ro,nosuid,nodev,noexec,relatime master:9 - cgroup cgroup rw,memory\n")
+			})
+
+			it("reads cpu.cfs_quota_us", func() {
+				write("sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us", "400000\n")
+				write("sys/fs/cgroup/cpu,cpuacct/cpu.cfs_period_us", "100000\n")
+
+				g.Expect(h.CPUCount()).To(Equal(4))
+			})
+
+			it("returns 0 if unlimited", func() {
+				write("sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us", "-1\n")
+				write("sys/fs/cgroup/cpu,cpuacct/cpu.cfs_period_us", "100000\n")
+
+				g.Expect(h.CPUCount()).To(Equal(0))
+			})
+		})
+
+		it("returns 0 without cgroups", func() {
+			g.Expect(h.CPUCount()).To(Equal(0))
+		})
+	})
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+// unlimited is the size at and above which a cgroup memory limit is treated as unset.  cgroup v1 reports an unset
+// limit as the largest page-aligned int64 rather than a sentinel string.
+const unlimited = memory.Size(1 << 62)
+
+var memTotalRE = regexp.MustCompile("(?m)^MemTotal:\\s+([\\d]+) kB$")
+
+// TotalMemory returns the memory limit of the process's cgroup, falling back to the total memory of the machine if
+// the cgroup is unlimited.
+func (h Host) TotalMemory() (memory.Size, error) {
+	l, ok, err := h.cgroupMemoryLimit()
+	if err != nil {
+		return memory.Size(0), err
+	}
+
+	if ok {
+		return l, nil
+	}
+
+	s, ok, err := h.readFile("proc", "meminfo")
+	if err != nil {
+		return memory.Size(0), err
+	}
+
+	if !ok || !memTotalRE.MatchString(s) {
+		return memory.Size(0), fmt.Errorf("unable to determine total memory from cgroup or %s", h.path("proc", "meminfo"))
+	}
+
+	i, err := strconv.ParseInt(memTotalRE.FindStringSubmatch(s)[1], 10, 64)
+	if err != nil {
+		return memory.Size(0), err
+	}
+
+	return memory.Size(i * memory.Kibi), nil
+}
+
+func (h Host) cgroupMemoryLimit() (memory.Size, bool, error) {
+	d, v2, err := h.cgroupDirectories("memory")
+	if err != nil {
+		return memory.Size(0), false, err
+	}
+
+	file := "memory.limit_in_bytes"
+	if v2 {
+		file = "memory.max"
+	}
+
+	limit, found := unlimited, false
+	for _, dir := range d {
+		s, ok, err := h.readFile(dir, file)
+		if err != nil {
+			return memory.Size(0), false, err
+		}
+
+		if !ok || s == "max" {
+			continue
+		}
+
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return memory.Size(0), false, fmt.Errorf("memory limit in %s is not an integer: %s", h.path(dir, file), s)
+		}
+
+		if l := memory.Size(i); l > 0 && l < limit {
+			limit, found = l, true
+		}
+	}
+
+	return limit, found, nil
+}
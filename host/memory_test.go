@@ -0,0 +1,130 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/host"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestTotalMemory(t *testing.T) {
+	spec.Run(t, "TotalMemory", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var h host.Host
+
+		write := func(path string, content string) {
+			f := filepath.Join(h.Root, path)
+			g.Expect(os.MkdirAll(filepath.Dir(f), 0755)).To(Succeed())
+			g.Expect(ioutil.WriteFile(f, []byte(content), 0644)).To(Succeed())
+		}
+
+		it.Before(func() {
+			var err error
+			h.Root, err = ioutil.TempDir("", "host")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			write("proc/meminfo", "MemTotal:        1024 kB\nMemFree:          512 kB\n")
+		})
+
+		it.After(func() {
+			g.Expect(os.RemoveAll(h.Root)).To(Succeed())
+		})
+
+		when("cgroup v2", func() {
+
+			it.Before(func() {
+				write("proc/self/cgroup", "0::/system.slice/app.service\n")
+				write("proc/self/mountinfo", "30 23 0:26 / /sys/fs/cgroup rw,nosuid,nodev,noexec,relatime shared:4 - cgroup2 cgroup2 rw,nsdelegate\n")
+			})
+
+			it("reads memory.max", func() {
+				write("sys/fs/cgroup/system.slice/app.service/memory.max", "1073741824\n")
+
+				g.Expect(h.TotalMemory()).To(Equal(memory.Size(memory.Gibi)))
+			})
+
+			it("uses the smallest limit of the hierarchy", func() {
+				write("sys/fs/cgroup/system.slice/app.service/memory.max", "max\n")
+				write("sys/fs/cgroup/system.slice/memory.max", "536870912\n")
+
+				g.Expect(h.TotalMemory()).To(Equal(memory.Size(512 * memory.Mibi)))
+			})
+
+			it("falls back to meminfo if unlimited", func() {
+				write("sys/fs/cgroup/system.slice/app.service/memory.max", "max\n")
+
+				g.Expect(h.TotalMemory()).To(Equal(memory.Size(memory.Mibi)))
+			})
+
+			it("reads memory.max from namespaced mount", func() {
+				write("proc/self/mountinfo", "30 23 0:26 /system.slice/app.service /sys/fs/cgroup rw,relatime - cgroup2 cgroup2 rw\n")
+				write("sys/fs/cgroup/memory.max", "1073741824\n")
+
+				g.Expect(h.TotalMemory()).To(Equal(memory.Size(memory.Gibi)))
+			})
+
+			it("returns error if limit is malformed", func() {
+				write("sys/fs/cgroup/system.slice/app.service/memory.max", "unknown\n")
+
+				_, err := h.TotalMemory()
+				g.Expect(err).To(HaveOccurred())
+			})
+		})
+
+		when("cgroup v1", func() {
+
+			it.Before(func() {
+				write("proc/self/cgroup", "12:cpu,cpuacct:/docker/abc\n11:memory:/docker/abc\n1:name=systemd:/docker/abc\n")
+				write("proc/self/mountinfo", "25 24 0:22 / /sys/fs/cgroup ro,nosuid,nodev,noexec - tmpfs tmpfs ro,mode=755\n"+
+					"26 25 0:23 /docker/abc /sys/fs/cgroup/cpu,cpuacct ro,nosuid,nodev,noexec,relatime master:8 - cgroup cgroup rw,cpu,cpuacct\n"+
+					"27 25 0:24 /docker/abc /sys/fs/cgroup/memory ro,nosuid,nodev,noexec,relatime master:9 - cgroup cgroup rw,memory\n")
+			})
+
+			it("reads memory.limit_in_bytes", func() {
+				write("sys/fs/cgroup/memory/memory.limit_in_bytes", "1073741824\n")
+
+				g.Expect(h.TotalMemory()).To(Equal(memory.Size(memory.Gibi)))
+			})
+
+			it("falls back to meminfo if unlimited", func() {
+				write("sys/fs/cgroup/memory/memory.limit_in_bytes", "9223372036854771712\n")
+
+				g.Expect(h.TotalMemory()).To(Equal(memory.Size(memory.Mibi)))
+			})
+		})
+
+		it("reads meminfo without cgroups", func() {
+			g.Expect(h.TotalMemory()).To(Equal(memory.Size(memory.Mibi)))
+		})
+
+		it("returns error without cgroups or meminfo", func() {
+			g.Expect(os.Remove(filepath.Join(h.Root, "proc", "meminfo"))).To(Succeed())
+
+			_, err := h.TotalMemory()
+			g.Expect(err).To(HaveOccurred())
+		})
+	})
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+// Defaults are the values the JVM uses for regions that are not configured.
+type Defaults struct {
+	// PermGen is whether class metadata is stored in the permanent generation (Java 7) rather than metaspace.
+	PermGen bool
+
+	// ReservedCodeCache is the code cache reserved with tiered compilation, and NonTieredReservedCodeCache without it.
+	ReservedCodeCache          memory.ReservedCodeCache
+	NonTieredReservedCodeCache memory.ReservedCodeCache
+
+	Stack memory.Stack
+
+	// TieredCompilation is whether tiered compilation is enabled unless configured otherwise.
+	TieredCompilation bool
+}
+
+// DefaultsFor returns the defaults of a Java major version.  A version of 0 is unknown and returns the defaults of
+// the current Java versions.
+func DefaultsFor(version int) Defaults {
+	d := Defaults{
+		PermGen:                    false,
+		ReservedCodeCache:          memory.DefaultReservedCodeCache,
+		NonTieredReservedCodeCache: memory.ReservedCodeCache(48 * memory.Mibi),
+		Stack:                      memory.DefaultStack,
+		TieredCompilation:          true,
+	}
+
+	if version != 0 && version <= 7 {
+		d.PermGen = true
+		d.TieredCompilation = false
+	}
+
+	return d
+}
+
+// CodeCache returns the reserved code cache for the given tiered compilation setting.
+func (d Defaults) CodeCache(tiered bool) memory.ReservedCodeCache {
+	if tiered {
+		return d.ReservedCodeCache
+	}
+
+	return d.NonTieredReservedCodeCache
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestDefaults(t *testing.T) {
+	spec.Run(t, "Defaults", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("uses current defaults if version is unknown", func() {
+			d := jvm.DefaultsFor(0)
+
+			g.Expect(d.PermGen).To(BeFalse())
+			g.Expect(d.CodeCache(d.TieredCompilation)).To(Equal(memory.DefaultReservedCodeCache))
+			g.Expect(d.Stack).To(Equal(memory.DefaultStack))
+		})
+
+		it("uses permanent generation and no tiered compilation for Java 7", func() {
+			d := jvm.DefaultsFor(7)
+
+			g.Expect(d.PermGen).To(BeTrue())
+			g.Expect(d.CodeCache(d.TieredCompilation)).To(Equal(memory.ReservedCodeCache(48 * memory.Mibi)))
+		})
+
+		it("uses metaspace and tiered compilation for Java 8", func() {
+			d := jvm.DefaultsFor(8)
+
+			g.Expect(d.PermGen).To(BeFalse())
+			g.Expect(d.CodeCache(d.TieredCompilation)).To(Equal(memory.DefaultReservedCodeCache))
+			g.Expect(d.CodeCache(false)).To(Equal(memory.ReservedCodeCache(48 * memory.Mibi)))
+		})
+	})
+}
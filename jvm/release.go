@@ -0,0 +1,109 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Vendor is the virtual machine implementation of a JVM.
+type Vendor string
+
+const (
+	VendorGraalVM = Vendor("graalvm")
+	VendorHotSpot = Vendor("hotspot")
+	VendorOpenJ9  = Vendor("openj9")
+)
+
+// Release describes a JDK or JRE as recorded in its release file.
+type Release struct {
+	Implementor string
+	JVMVariant  string
+	Vendor      Vendor
+
+	// Version is the major version parsed from JAVA_VERSION.
+	Version int
+}
+
+// ReadRelease reads the release file of a JDK or JRE.
+func ReadRelease(javaHome string) (Release, error) {
+	f, err := os.Open(filepath.Join(javaHome, "release"))
+	if err != nil {
+		return Release{}, fmt.Errorf("unable to open release file: %w", err)
+	}
+	defer f.Close()
+
+	return ParseRelease(f)
+}
+
+// ParseRelease parses the contents of a release file.  JAVA_VERSION is required.  The vendor is OpenJ9 if
+// JVM_VARIANT is OpenJ9, GraalVM if GRAALVM_VERSION is present or IMPLEMENTOR mentions GraalVM, and HotSpot otherwise.
+func ParseRelease(in io.Reader) (Release, error) {
+	properties := make(map[string]string)
+
+	s := bufio.NewScanner(in)
+	for s.Scan() {
+		if k, v := splitProperty(s.Text()); k != "" {
+			properties[k] = v
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return Release{}, fmt.Errorf("unable to read release file: %w", err)
+	}
+
+	v, ok := properties["JAVA_VERSION"]
+	if !ok {
+		return Release{}, fmt.Errorf("release file does not contain JAVA_VERSION")
+	}
+
+	version, err := ParseVersion(v)
+	if err != nil {
+		return Release{}, err
+	}
+
+	r := Release{
+		Implementor: properties["IMPLEMENTOR"],
+		JVMVariant:  properties["JVM_VARIANT"],
+		Vendor:      VendorHotSpot,
+		Version:     version,
+	}
+
+	if _, ok := properties["GRAALVM_VERSION"]; ok || strings.Contains(strings.ToLower(r.Implementor), "graalvm") {
+		r.Vendor = VendorGraalVM
+	}
+
+	if strings.EqualFold(r.JVMVariant, "openj9") {
+		r.Vendor = VendorOpenJ9
+	}
+
+	return r, nil
+}
+
+func splitProperty(line string) (string, string) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+
+	return strings.TrimSpace(parts[0]), strings.Trim(strings.TrimSpace(parts[1]), `"`)
+}
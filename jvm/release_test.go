@@ -0,0 +1,96 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestRelease(t *testing.T) {
+	spec.Run(t, "Release", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("parses HotSpot release", func() {
+			g.Expect(jvm.ParseRelease(strings.NewReader(`IMPLEMENTOR="AdoptOpenJDK"
+JAVA_VERSION="1.8.0_252"
+JVM_VARIANT="Hotspot"
+OS_NAME="Linux"
+`))).To(Equal(jvm.Release{Implementor: "AdoptOpenJDK", JVMVariant: "Hotspot", Vendor: jvm.VendorHotSpot, Version: 8}))
+		})
+
+		it("parses OpenJ9 release", func() {
+			g.Expect(jvm.ParseRelease(strings.NewReader(`IMPLEMENTOR="IBM Corporation"
+JAVA_VERSION="11.0.7"
+JVM_VARIANT="Openj9"
+`))).To(Equal(jvm.Release{Implementor: "IBM Corporation", JVMVariant: "Openj9", Vendor: jvm.VendorOpenJ9, Version: 11}))
+		})
+
+		it("parses GraalVM release", func() {
+			g.Expect(jvm.ParseRelease(strings.NewReader(`IMPLEMENTOR="GraalVM Community"
+JAVA_VERSION="11.0.7"
+GRAALVM_VERSION="20.1.0"
+`))).To(Equal(jvm.Release{Implementor: "GraalVM Community", Vendor: jvm.VendorGraalVM, Version: 11}))
+
+			g.Expect(jvm.ParseRelease(strings.NewReader(`IMPLEMENTOR="Oracle Corporation"
+JAVA_VERSION="17.0.1"
+GRAALVM_VERSION="21.3.0"
+`))).To(Equal(jvm.Release{Implementor: "Oracle Corporation", Vendor: jvm.VendorGraalVM, Version: 17}))
+		})
+
+		it("returns error if release file does not contain version", func() {
+			_, err := jvm.ParseRelease(strings.NewReader("OS_NAME=\"Linux\"\n"))
+			g.Expect(err).To(MatchError("release file does not contain JAVA_VERSION"))
+		})
+
+		when("reading", func() {
+
+			var path string
+
+			it.Before(func() {
+				var err error
+				path, err = ioutil.TempDir("", "release")
+				g.Expect(err).NotTo(HaveOccurred())
+			})
+
+			it.After(func() {
+				g.Expect(os.RemoveAll(path)).To(Succeed())
+			})
+
+			it("reads release file from Java home", func() {
+				g.Expect(ioutil.WriteFile(filepath.Join(path, "release"), []byte("JAVA_VERSION=\"1.7.0_80\"\n"), 0644)).To(Succeed())
+
+				r, err := jvm.ReadRelease(path)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(r.Version).To(Equal(7))
+			})
+
+			it("returns error if release file does not exist", func() {
+				_, err := jvm.ReadRelease(path)
+				g.Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"math/bits"
+)
+
+// FixedThreadCount is the number of threads the JVM starts regardless of the number of CPUs, e.g. the VM thread,
+// reference handler, finalizer, signal dispatcher, service thread and attach listener.
+const FixedThreadCount = 9
+
+// InternalThreadCount estimates the number of threads the JVM starts for itself with a number of CPUs: the fixed
+// threads, parallel and concurrent garbage collector threads, and compiler threads.
+func InternalThreadCount(cpus int) int {
+	if cpus < 1 {
+		cpus = 1
+	}
+
+	return FixedThreadCount + ParallelGCThreads(cpus) + ConcGCThreads(cpus) + CompilerThreads(cpus)
+}
+
+// ParallelGCThreads returns the JVM's default for -XX:ParallelGCThreads with a number of CPUs.
+func ParallelGCThreads(cpus int) int {
+	if cpus <= 8 {
+		return cpus
+	}
+
+	return 8 + (cpus-8)*5/8
+}
+
+// ConcGCThreads returns the JVM's default for -XX:ConcGCThreads with a number of CPUs.
+func ConcGCThreads(cpus int) int {
+	n := (ParallelGCThreads(cpus) + 2) / 4
+	if n < 1 {
+		return 1
+	}
+
+	return n
+}
+
+// CompilerThreads returns the JVM's default for -XX:CICompilerCount with tiered compilation and a number of CPUs.
+func CompilerThreads(cpus int) int {
+	logCPUs := log2(cpus)
+	logLogCPUs := log2(logCPUs)
+
+	n := logCPUs * logLogCPUs * 3 / 2
+	if n < 2 {
+		return 2
+	}
+
+	return n
+}
+
+func log2(n int) int {
+	if n < 1 {
+		n = 1
+	}
+
+	return bits.Len(uint(n)) - 1
+}
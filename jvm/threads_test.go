@@ -0,0 +1,55 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestThreads(t *testing.T) {
+	spec.Run(t, "Threads", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("uses one parallel GC thread per CPU up to 8 CPUs", func() {
+			g.Expect(jvm.ParallelGCThreads(1)).To(Equal(1))
+			g.Expect(jvm.ParallelGCThreads(8)).To(Equal(8))
+			g.Expect(jvm.ParallelGCThreads(16)).To(Equal(13))
+		})
+
+		it("uses a quarter of parallel GC threads for concurrent GC threads", func() {
+			g.Expect(jvm.ConcGCThreads(1)).To(Equal(1))
+			g.Expect(jvm.ConcGCThreads(8)).To(Equal(2))
+			g.Expect(jvm.ConcGCThreads(16)).To(Equal(3))
+		})
+
+		it("scales compiler threads logarithmically", func() {
+			g.Expect(jvm.CompilerThreads(1)).To(Equal(2))
+			g.Expect(jvm.CompilerThreads(8)).To(Equal(4))
+			g.Expect(jvm.CompilerThreads(16)).To(Equal(12))
+		})
+
+		it("estimates internal threads", func() {
+			g.Expect(jvm.InternalThreadCount(2)).To(Equal(14))
+			g.Expect(jvm.InternalThreadCount(0)).To(Equal(jvm.InternalThreadCount(1)))
+		})
+	})
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var versionRE = regexp.MustCompile(`^(?:1\.)?([0-9]+)`)
+
+// ParseVersion returns the major version of a Java version string such as 1.8.0_252, 11.0.7 or 17.
+func ParseVersion(s string) (int, error) {
+	groups := versionRE.FindStringSubmatch(s)
+	if groups == nil {
+		return 0, fmt.Errorf("version does not match pattern '%s': %s", versionRE.String(), s)
+	}
+
+	return strconv.Atoi(groups[1])
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jvm_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestVersion(t *testing.T) {
+	spec.Run(t, "Version", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("parses legacy versions", func() {
+			g.Expect(jvm.ParseVersion("1.7.0_80")).To(Equal(7))
+			g.Expect(jvm.ParseVersion("1.8.0_252")).To(Equal(8))
+		})
+
+		it("parses modern versions", func() {
+			g.Expect(jvm.ParseVersion("11")).To(Equal(11))
+			g.Expect(jvm.ParseVersion("11.0.7")).To(Equal(11))
+			g.Expect(jvm.ParseVersion("17-ea")).To(Equal(17))
+		})
+
+		it("does not parse invalid versions", func() {
+			_, err := jvm.ParseVersion("latest")
+			g.Expect(err).To(HaveOccurred())
+		})
+	})
+}
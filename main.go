@@ -17,59 +17,223 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"strings"
 
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/application"
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/config"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/environment"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/execd"
 	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/host"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/output"
 	flag "github.com/spf13/pflag"
 )
 
+const (
+	exitInvalidInput       = 1
+	exitInsufficientMemory = 2
+	exitFailure            = 3
+)
+
 func main() {
+	a := flags.DefaultApplicationPath
+	b := flags.DefaultBaselineThreadCount
+	cpu := flags.DefaultCPUCount
+	cf := flags.DefaultConfig
+	f := flags.DefaultClassLoadFactor
 	h := flags.DefaultHeadRoom
+	hs := flags.DefaultHeadRoomSize
+	hm := flags.DefaultHeapMode
+	i := flags.DefaultInitialHeapPercentage
 	j := flags.DefaultJVMOptions
+	jh := flags.DefaultJavaHome
+	v := flags.DefaultJVMVersion
+	e := flags.DefaultJVMVendor
 	l := flags.DefaultLoadedClassCount
+	mcc := flags.DefaultMinCodeCache
+	mdm := flags.DefaultMinDirectMemory
+	mh := flags.DefaultMinHeap
+	ms := flags.DefaultMinStack
+	r := flags.DefaultJREClassCount
 	t := flags.DefaultThreadCount
 	m := flags.DefaultTotalMemory
-
-	c := calculator.Calculator{HeadRoom: &h, JvmOptions: &j, LoadedClassCount: &l, ThreadCount: &t, TotalMemory: &m}
-
-	flag.Var(c.HeadRoom, flags.FlagHeadRoom, "percentage of total memory available which will be left unallocated to cover JVM overhead")
+	o := flags.DefaultOutput
+	p := flags.DefaultProfile
+
+	var explain, execD, merge bool
+	var execDPath string
+
+	c := calculator.Calculator{CPUCount: &cpu, HeadRoom: &h, HeadRoomSize: &hs, HeapMode: &hm, InitialHeapPercentage: &i, JvmOptions: &j, JvmVendor: &e, JvmVersion: &v, LoadedClassCount: &l, MinCodeCache: &mcc, MinDirectMemory: &mdm, MinHeap: &mh, MinStack: &ms, ThreadCount: &t, TotalMemory: &m}
+
+	flag.Var(&a, flags.FlagApplicationPath, "path to the application directory or archive, used to estimate --loaded-class-count and --thread-count if not specified")
+	flag.Var(&b, flags.FlagBaselineThreadCount, "the number of threads other than request processing threads and JVM threads, used to estimate --thread-count")
+	flag.Var(&f, flags.FlagClassLoadFactor, "proportion of application and JRE classes that are loaded, used to estimate --loaded-class-count")
+	flag.Var(&cf, flags.FlagConfig, "path to a YAML file specifying flags, used for flags that are not specified on the command line or in the environment")
+	flag.Var(c.CPUCount, flags.FlagCPUCount, "the number of CPUs available to the JVM, used to estimate its internal threads, detected from the container CPU quota if not specified, 0 to not account for internal threads")
+	flag.BoolVar(&execD, execd.FlagExecD, false, "run as a Cloud Native Buildpacks exec.d helper, reading --jvm-options from JAVA_TOOL_OPTIONS only and writing JAVA_TOOL_OPTIONS as TOML to file descriptor 3 instead of --output, implied if the executable is in an exec.d directory")
+	flag.StringVar(&execDPath, execd.FlagExecDPath, "", "path to write the exec.d TOML to instead of file descriptor 3")
+	flag.BoolVar(&explain, "explain", false, "print each step of the calculation to stderr")
+	flag.Var(flags.HeadRoomValue{Percentage: c.HeadRoom, Size: c.HeadRoomSize}, flags.FlagHeadRoom, "memory which will be left unallocated to cover JVM overhead, as a percentage of total memory (e.g. 5 or 2.5%), a size (e.g. 64M) or the larger of both (e.g. 5%,64M)")
+	flag.Var(c.HeapMode, flags.FlagHeapMode, "how the heap is expressed, one of size (-Xmx) or percentage (-XX:MaxRAMPercentage)")
+	flag.Var(c.InitialHeapPercentage, flags.FlagInitialHeapPercentage, "percentage of the heap to use as the initial heap (-Xms), 0 to leave the initial heap unset")
+	flag.Var(&jh, flags.FlagJavaHome, "path to the JDK or JRE, typically JAVA_HOME, used to detect the JVM vendor and --jvm-version if not specified")
+	flag.Var(&r, flags.FlagJREClassCount, "the number of classes in the JRE, used to estimate --loaded-class-count")
 	flag.Var(c.JvmOptions, flags.FlagJVMOptions, "JVM options, typically JAVA_OPTS")
+	flag.Var(c.JvmVendor, flags.FlagJVMVendor, "JVM implementation, one of graalvm, hotspot or openj9, detected from --java-home if not specified")
+	flag.Var(c.JvmVersion, flags.FlagJVMVersion, "major version of the JVM, used to select JVM defaults")
+	flag.BoolVar(&merge, "merge-jvm-options", false, "print the effective --jvm-options, verbatim, together with the calculated options instead of the calculated options alone")
+	flag.Var(c.MinCodeCache, flags.FlagMinCodeCache, "size, typically expressed with size classification (B, K, M, G, T), below which the code cache is not reduced to fit --min-heap")
+	flag.Var(c.MinDirectMemory, flags.FlagMinDirectMemory, "size, typically expressed with size classification (B, K, M, G, T), below which direct memory is not reduced to fit --min-heap")
+	flag.Var(c.MinHeap, flags.FlagMinHeap, "minimum heap, typically expressed with size classification (B, K, M, G, T), to which code cache, thread stack and direct memory are reduced if required")
+	flag.Var(c.MinStack, flags.FlagMinStack, "size, typically expressed with size classification (B, K, M, G, T), below which the thread stack is not reduced to fit --min-heap")
+	flag.Var(&o, flags.FlagOutput, "output format, one of flags, json, env, shell or argfile")
+	flag.Var(&p, flags.FlagProfile, "name of a profile in --config whose flags override the top-level flags")
 	flag.Var(c.LoadedClassCount, flags.FlagLoadedClassCount, "the number of classes that will be loaded when the application is running")
-	flag.Var(c.ThreadCount, flags.FlagThreadCount, "the number of user threads")
-	flag.Var(c.TotalMemory, "total-memory", "total memory available to the application, typically expressed with size classification (B, K, M, G, T)")
+	flag.Var(c.ThreadCount, flags.FlagThreadCount, "the number of user threads, estimated from --application-path if not specified")
+	flag.Var(c.TotalMemory, flags.FlagTotalMemory, "total memory available to the application, typically expressed with size classification (B, K, M, G, T), detected from the container memory limit if not specified")
 	flag.Parse()
 
-	if !validate(c.HeadRoom, c.JvmOptions, c.LoadedClassCount, c.ThreadCount, c.TotalMemory) {
+	if !flag.CommandLine.Changed(execd.FlagExecD) && execd.IsHelper(os.Args[0]) {
+		execD = true
+	}
+
+	apply := environment.Apply
+	if execD {
+		apply = environment.ApplyExecD
+	}
+
+	if err := apply(flag.CommandLine, os.LookupEnv); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitInvalidInput)
+	}
+
+	if p != "" && cf == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "--%s requires --%s\n", flags.FlagProfile, flags.FlagConfig)
+		os.Exit(exitInvalidInput)
+	}
+
+	// JVM options only specified in --config are printed with the calculated options as they do not otherwise reach
+	// the JVM
+	var configured []string
+
+	if cf != "" {
+		cfg, err := config.Read(string(cf))
+		if err == nil {
+			configured, err = cfg.Apply(flag.CommandLine, string(p))
+		}
+
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitInvalidInput)
+		}
+	}
+
+	if !flag.CommandLine.Changed(flags.FlagTotalMemory) {
+		if t, err := (host.Host{Root: host.DefaultRoot}).TotalMemory(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to detect --%s: %s\n", flags.FlagTotalMemory, err)
+		} else {
+			*c.TotalMemory = flags.TotalMemory(t)
+		}
+	}
+
+	if !flag.CommandLine.Changed(flags.FlagCPUCount) {
+		if n, err := (host.Host{Root: host.DefaultRoot}).CPUCount(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to detect --%s: %s\n", flags.FlagCPUCount, err)
+		} else {
+			*c.CPUCount = flags.CPUCount(n)
+		}
+	}
+
+	if jh != "" {
+		if release, err := jvm.ReadRelease(string(jh)); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to read JVM release from --%s: %s\n", flags.FlagJavaHome, err)
+		} else {
+			c.Release = &release
+		}
+	}
+
+	if !flag.CommandLine.Changed(flags.FlagLoadedClassCount) && a != "" {
+		if n, err := application.ClassCount(string(a)); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to estimate --%s: %s\n", flags.FlagLoadedClassCount, err)
+		} else {
+			*c.LoadedClassCount = flags.LoadedClassCount(application.LoadedClassCount(n, int(r), float64(f)))
+		}
+	}
+
+	if !flag.CommandLine.Changed(flags.FlagThreadCount) && a != "" {
+		if t, err := application.RequestThreadCount(string(a)); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "unable to estimate --%s: %s\n", flags.FlagThreadCount, err)
+		} else {
+			source := t.Source
+			if source == "" {
+				source = "default"
+			}
+
+			*c.ThreadCount = flags.ThreadCount(t.Count + int(b))
+			_, _ = fmt.Fprintf(os.Stderr, "estimated --%s=%d: %d request threads (%s) + %d baseline threads\n",
+				flags.FlagThreadCount, *c.ThreadCount, t.Count, source, b)
+		}
+	}
+
+	if !validate(&a, &b, &cf, c.CPUCount, &f, c.HeadRoom, c.HeadRoomSize, c.HeapMode, c.InitialHeapPercentage, &jh, &r, c.JvmOptions, c.JvmVendor, c.JvmVersion, c.LoadedClassCount, c.MinCodeCache, c.MinDirectMemory, c.MinHeap, c.MinStack, &o, &p, c.ThreadCount, c.TotalMemory) {
 		_, _ = fmt.Fprintln(os.Stderr, "")
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(exitInvalidInput)
 	}
 
-	o, err := c.Calculate()
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, err.Error())
-		os.Exit(2)
+	result, err := c.CalculateResult()
+
+	var insufficient *calculator.InsufficientMemoryError
+	if explain && err == nil {
+		_ = output.Explain(os.Stderr, c, result)
+	} else if explain && errors.As(err, &insufficient) {
+		_ = output.Explain(os.Stderr, c, insufficient.Result)
 	}
 
-	s := make([]string, len(o))
-	for i, t := range o {
-		s[i] = t.String()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+
+		if errors.As(err, &insufficient) {
+			os.Exit(exitInsufficientMemory)
+		}
+
+		os.Exit(exitInvalidInput)
 	}
 
-	fmt.Println(output(o))
-}
+	for _, region := range result.Reduced() {
+		_, _ = fmt.Fprintf(os.Stderr, "reduced %s to fit --%s\n", region, flags.FlagMinHeap)
+	}
 
-func output(o []fmt.Stringer) string {
-	s := make([]string, len(o))
+	options, existing := append(output.Options(result), configured...), os.Getenv(environment.JavaToolOptions)
+	if merge {
+		// JAVA_TOOL_OPTIONS is replaced as it is always read into --jvm-options, so the merged options are the complete
+		// configuration
+		options, existing = output.MergedOptions(result, c.JvmOptions), ""
+	}
 
-	for i, t := range o {
-		s[i] = t.String()
+	switch {
+	case execD:
+		err = execd.Write(execDPath, options, existing)
+	case o == flags.OutputArgFile:
+		err = output.ArgFile(os.Stdout, options)
+	case o == flags.OutputEnv:
+		err = output.Env(os.Stdout, options, existing)
+	case o == flags.OutputJSON:
+		err = output.JSON(os.Stdout, c, result, options)
+	case o == flags.OutputShell:
+		err = output.Shell(os.Stdout, options, existing)
+	default:
+		err = output.Flags(os.Stdout, options)
 	}
 
-	return strings.Join(s, " ")
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitFailure)
+	}
 }
 
 func validate(vs ...flags.Validatable) bool {
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const DefaultCodeCacheTotal = CodeCacheTotal(256 * Mibi)
+
+var codeCacheTotalRE = regexp.MustCompile(fmt.Sprintf("^-Xcodecachetotal(%s)$", sizePattern))
+
+// CodeCacheTotal is the total size of the OpenJ9 JIT code cache.
+type CodeCacheTotal Size
+
+func IsCodeCacheTotal(s string) bool {
+	return codeCacheTotalRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseCodeCacheTotal(s string) (CodeCacheTotal, error) {
+	t := strings.TrimSpace(s)
+
+	if !codeCacheTotalRE.MatchString(t) {
+		return CodeCacheTotal(0), fmt.Errorf("code cache total does not match pattern '%s': %s", codeCacheTotalRE.String(), t)
+	}
+
+	groups := codeCacheTotalRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return CodeCacheTotal(0), err
+	}
+
+	return CodeCacheTotal(size), nil
+}
+
+func (c CodeCacheTotal) String() string {
+	return fmt.Sprintf("-Xcodecachetotal%s", Size(c))
+}
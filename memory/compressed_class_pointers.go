@@ -0,0 +1,52 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var compressedClassPointersRE = regexp.MustCompile("^-XX:([+-])UseCompressedClassPointers$")
+
+// CompressedClassPointers is whether class pointers are compressed and class metadata is therefore stored in the
+// compressed class space.
+type CompressedClassPointers bool
+
+func IsCompressedClassPointers(s string) bool {
+	return compressedClassPointersRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseCompressedClassPointers(s string) (CompressedClassPointers, error) {
+	t := strings.TrimSpace(s)
+
+	if !compressedClassPointersRE.MatchString(t) {
+		return CompressedClassPointers(false), fmt.Errorf("compressed class pointers does not match pattern '%s': %s", compressedClassPointersRE.String(), t)
+	}
+
+	groups := compressedClassPointersRE.FindStringSubmatch(t)
+	return CompressedClassPointers(groups[1] == "+"), nil
+}
+
+func (c CompressedClassPointers) String() string {
+	if c {
+		return "-XX:+UseCompressedClassPointers"
+	}
+
+	return "-XX:-UseCompressedClassPointers"
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestCompressedClassPointers(t *testing.T) {
+	spec.Run(t, "CompressedClassPointers", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("formats", func() {
+			g.Expect(memory.CompressedClassPointers(true).String()).To(Equal("-XX:+UseCompressedClassPointers"))
+			g.Expect(memory.CompressedClassPointers(false).String()).To(Equal("-XX:-UseCompressedClassPointers"))
+		})
+
+		it("matches -XX:±UseCompressedClassPointers", func() {
+			g.Expect(memory.IsCompressedClassPointers("-XX:+UseCompressedClassPointers")).To(BeTrue())
+			g.Expect(memory.IsCompressedClassPointers("-XX:-UseCompressedClassPointers")).To(BeTrue())
+		})
+
+		it("does not match non -XX:±UseCompressedClassPointers", func() {
+			g.Expect(memory.IsCompressedClassPointers("-XX:-UseCompressedOops")).To(BeFalse())
+		})
+
+		it("parses", func() {
+			g.Expect(memory.ParseCompressedClassPointers("-XX:+UseCompressedClassPointers")).To(Equal(memory.CompressedClassPointers(true)))
+			g.Expect(memory.ParseCompressedClassPointers("-XX:-UseCompressedClassPointers")).To(Equal(memory.CompressedClassPointers(false)))
+		})
+
+	})
+}
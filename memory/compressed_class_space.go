@@ -0,0 +1,51 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var compressedClassSpaceRE = regexp.MustCompile(fmt.Sprintf("^-XX:CompressedClassSpaceSize=(%s)$", sizePattern))
+
+type CompressedClassSpace Size
+
+func IsCompressedClassSpace(s string) bool {
+	return compressedClassSpaceRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseCompressedClassSpace(s string) (CompressedClassSpace, error) {
+	t := strings.TrimSpace(s)
+
+	if !compressedClassSpaceRE.MatchString(t) {
+		return CompressedClassSpace(0), fmt.Errorf("compressed class space does not match pattern '%s': %s", compressedClassSpaceRE.String(), t)
+	}
+
+	groups := compressedClassSpaceRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return CompressedClassSpace(0), err
+	}
+
+	return CompressedClassSpace(size), nil
+}
+
+func (c CompressedClassSpace) String() string {
+	return fmt.Sprintf("-XX:CompressedClassSpaceSize=%s", Size(c))
+}
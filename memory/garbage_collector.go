@@ -0,0 +1,55 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	GarbageCollectorG1         = GarbageCollector("G1")
+	GarbageCollectorParallel   = GarbageCollector("Parallel")
+	GarbageCollectorSerial     = GarbageCollector("Serial")
+	GarbageCollectorShenandoah = GarbageCollector("Shenandoah")
+	GarbageCollectorZ          = GarbageCollector("Z")
+)
+
+var garbageCollectorRE = regexp.MustCompile("^-XX:\\+Use(G1|Parallel|Serial|Shenandoah|Z)GC$")
+
+// GarbageCollector is the collector selected with -XX:+Use<name>GC.
+type GarbageCollector string
+
+func IsGarbageCollector(s string) bool {
+	return garbageCollectorRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseGarbageCollector(s string) (GarbageCollector, error) {
+	t := strings.TrimSpace(s)
+
+	if !garbageCollectorRE.MatchString(t) {
+		return GarbageCollector(""), fmt.Errorf("garbage collector does not match pattern '%s': %s", garbageCollectorRE.String(), t)
+	}
+
+	groups := garbageCollectorRE.FindStringSubmatch(t)
+	return GarbageCollector(groups[1]), nil
+}
+
+func (g GarbageCollector) String() string {
+	return fmt.Sprintf("-XX:+Use%sGC", string(g))
+}
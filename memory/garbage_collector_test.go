@@ -0,0 +1,55 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestGarbageCollector(t *testing.T) {
+	spec.Run(t, "GarbageCollector", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("formats", func() {
+			g.Expect(memory.GarbageCollectorG1.String()).To(Equal("-XX:+UseG1GC"))
+			g.Expect(memory.GarbageCollectorZ.String()).To(Equal("-XX:+UseZGC"))
+		})
+
+		it("matches -XX:+Use<name>GC", func() {
+			g.Expect(memory.IsGarbageCollector("-XX:+UseG1GC")).To(BeTrue())
+			g.Expect(memory.IsGarbageCollector("-XX:+UseParallelGC")).To(BeTrue())
+			g.Expect(memory.IsGarbageCollector("-XX:+UseSerialGC")).To(BeTrue())
+			g.Expect(memory.IsGarbageCollector("-XX:+UseShenandoahGC")).To(BeTrue())
+			g.Expect(memory.IsGarbageCollector("-XX:+UseZGC")).To(BeTrue())
+		})
+
+		it("does not match non -XX:+Use<name>GC", func() {
+			g.Expect(memory.IsGarbageCollector("-XX:-UseG1GC")).To(BeFalse())
+			g.Expect(memory.IsGarbageCollector("-XX:+UseGCOverheadLimit")).To(BeFalse())
+		})
+
+		it("parses", func() {
+			g.Expect(memory.ParseGarbageCollector("-XX:+UseShenandoahGC")).To(Equal(memory.GarbageCollectorShenandoah))
+		})
+
+	})
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var initialRAMPercentageRE = regexp.MustCompile("^-XX:InitialRAMPercentage=([\\d]+(?:\\.[\\d]+)?)$")
+
+// InitialRAMPercentage is the percentage of physical memory used for the initial heap.
+type InitialRAMPercentage float64
+
+func IsInitialRAMPercentage(s string) bool {
+	return initialRAMPercentageRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseInitialRAMPercentage(s string) (InitialRAMPercentage, error) {
+	t := strings.TrimSpace(s)
+
+	if !initialRAMPercentageRE.MatchString(t) {
+		return InitialRAMPercentage(0), fmt.Errorf("initial RAM percentage does not match pattern '%s': %s", initialRAMPercentageRE.String(), t)
+	}
+
+	groups := initialRAMPercentageRE.FindStringSubmatch(t)
+	percentage, err := strconv.ParseFloat(groups[1], 64)
+	if err != nil {
+		return InitialRAMPercentage(0), fmt.Errorf("initial RAM percentage is not a number: %s", groups[1])
+	}
+
+	return InitialRAMPercentage(percentage), nil
+}
+
+func (i InitialRAMPercentage) String() string {
+	return fmt.Sprintf("-XX:InitialRAMPercentage=%.2f", float64(i))
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestInitialRAMPercentage(t *testing.T) {
+	spec.Run(t, "InitialRAMPercentage", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		it("formats", func() {
+			g.Expect(memory.InitialRAMPercentage(75).String()).To(Equal("-XX:InitialRAMPercentage=75.00"))
+			g.Expect(memory.InitialRAMPercentage(12.345).String()).To(Equal("-XX:InitialRAMPercentage=12.35"))
+		})
+
+		it("matches -XX:InitialRAMPercentage", func() {
+			g.Expect(memory.IsInitialRAMPercentage("-XX:InitialRAMPercentage=75")).To(BeTrue())
+			g.Expect(memory.IsInitialRAMPercentage("-XX:InitialRAMPercentage=75.5")).To(BeTrue())
+		})
+
+		it("does not match non -XX:InitialRAMPercentage", func() {
+			g.Expect(memory.IsInitialRAMPercentage("-XX:InitialRAMPercentage=75%")).To(BeFalse())
+			g.Expect(memory.IsInitialRAMPercentage("-XX:MaxRAM=1G")).To(BeFalse())
+		})
+
+		it("parses", func() {
+			g.Expect(memory.ParseInitialRAMPercentage("-XX:InitialRAMPercentage=75")).To(Equal(memory.InitialRAMPercentage(75)))
+			g.Expect(memory.ParseInitialRAMPercentage("-XX:InitialRAMPercentage=12.5")).To(Equal(memory.InitialRAMPercentage(12.5)))
+		})
+
+	})
+}
@@ -22,7 +22,7 @@ import (
 	"strings"
 )
 
-var maxHeapRE = regexp.MustCompile(fmt.Sprintf("^-Xmx(%s)$", sizePattern))
+var maxHeapRE = regexp.MustCompile(fmt.Sprintf("^(?:-Xmx|-XX:MaxHeapSize=)(%s)$", sizePattern))
 
 type MaxHeap Size
 
@@ -45,5 +45,13 @@ func TestMaxHeap(t *testing.T) {
 			g.Expect(memory.ParseMaxHeap("-Xmx1K")).To(Equal(memory.MaxHeap(memory.Kibi)))
 		})
 
+		it("matches -XX:MaxHeapSize", func() {
+			g.Expect(memory.IsMaxHeap("-XX:MaxHeapSize=512m")).To(BeTrue())
+		})
+
+		it("parses -XX:MaxHeapSize", func() {
+			g.Expect(memory.ParseMaxHeap("-XX:MaxHeapSize=512m")).To(Equal(memory.MaxHeap(512 * memory.Mibi)))
+		})
+
 	})
 }
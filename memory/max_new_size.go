@@ -0,0 +1,51 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var maxNewSizeRE = regexp.MustCompile(fmt.Sprintf("^-XX:MaxNewSize=(%s)$", sizePattern))
+
+type MaxNewSize Size
+
+func IsMaxNewSize(s string) bool {
+	return maxNewSizeRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseMaxNewSize(s string) (MaxNewSize, error) {
+	t := strings.TrimSpace(s)
+
+	if !maxNewSizeRE.MatchString(t) {
+		return MaxNewSize(0), fmt.Errorf("max new size does not match pattern '%s': %s", maxNewSizeRE.String(), t)
+	}
+
+	groups := maxNewSizeRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return MaxNewSize(0), err
+	}
+
+	return MaxNewSize(size), nil
+}
+
+func (m MaxNewSize) String() string {
+	return fmt.Sprintf("-XX:MaxNewSize=%s", Size(m))
+}
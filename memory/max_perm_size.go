@@ -0,0 +1,51 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var maxPermSizeRE = regexp.MustCompile(fmt.Sprintf("^-XX:MaxPermSize=(%s)$", sizePattern))
+
+type MaxPermSize Size
+
+func IsMaxPermSize(s string) bool {
+	return maxPermSizeRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseMaxPermSize(s string) (MaxPermSize, error) {
+	t := strings.TrimSpace(s)
+
+	if !maxPermSizeRE.MatchString(t) {
+		return MaxPermSize(0), fmt.Errorf("max perm size does not match pattern '%s': %s", maxPermSizeRE.String(), t)
+	}
+
+	groups := maxPermSizeRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return MaxPermSize(0), err
+	}
+
+	return MaxPermSize(size), nil
+}
+
+func (m MaxPermSize) String() string {
+	return fmt.Sprintf("-XX:MaxPermSize=%s", Size(m))
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var maxRAMRE = regexp.MustCompile(fmt.Sprintf("^-XX:MaxRAM=(%s)$", sizePattern))
+
+// MaxRAM limits the physical memory the JVM uses to size the heap.
+type MaxRAM Size
+
+func IsMaxRAM(s string) bool {
+	return maxRAMRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseMaxRAM(s string) (MaxRAM, error) {
+	t := strings.TrimSpace(s)
+
+	if !maxRAMRE.MatchString(t) {
+		return MaxRAM(0), fmt.Errorf("max RAM does not match pattern '%s': %s", maxRAMRE.String(), t)
+	}
+
+	groups := maxRAMRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return MaxRAM(0), err
+	}
+
+	return MaxRAM(size), nil
+}
+
+func (m MaxRAM) String() string {
+	return fmt.Sprintf("-XX:MaxRAM=%s", Size(m))
+}
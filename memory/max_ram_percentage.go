@@ -0,0 +1,56 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const DefaultMaxRAMPercentage = MaxRAMPercentage(25)
+
+var maxRAMPercentageRE = regexp.MustCompile("^-XX:MaxRAMPercentage=([\\d]+(?:\\.[\\d]+)?)$")
+
+// MaxRAMPercentage is the percentage of physical memory used for the heap if physical memory is large.
+type MaxRAMPercentage float64
+
+func IsMaxRAMPercentage(s string) bool {
+	return maxRAMPercentageRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseMaxRAMPercentage(s string) (MaxRAMPercentage, error) {
+	t := strings.TrimSpace(s)
+
+	if !maxRAMPercentageRE.MatchString(t) {
+		return MaxRAMPercentage(0), fmt.Errorf("max RAM percentage does not match pattern '%s': %s", maxRAMPercentageRE.String(), t)
+	}
+
+	groups := maxRAMPercentageRE.FindStringSubmatch(t)
+	percentage, err := strconv.ParseFloat(groups[1], 64)
+	if err != nil {
+		return MaxRAMPercentage(0), fmt.Errorf("max RAM percentage is not a number: %s", groups[1])
+	}
+
+	return MaxRAMPercentage(percentage), nil
+}
+
+// String formats the percentage with a fractional part as some JVMs reject integer values.
+func (m MaxRAMPercentage) String() string {
+	return fmt.Sprintf("-XX:MaxRAMPercentage=%.2f", float64(m))
+}
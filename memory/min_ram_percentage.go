@@ -0,0 +1,55 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const DefaultMinRAMPercentage = MinRAMPercentage(50)
+
+var minRAMPercentageRE = regexp.MustCompile("^-XX:MinRAMPercentage=([\\d]+(?:\\.[\\d]+)?)$")
+
+// MinRAMPercentage is the percentage of physical memory used for the heap if physical memory is small.
+type MinRAMPercentage float64
+
+func IsMinRAMPercentage(s string) bool {
+	return minRAMPercentageRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseMinRAMPercentage(s string) (MinRAMPercentage, error) {
+	t := strings.TrimSpace(s)
+
+	if !minRAMPercentageRE.MatchString(t) {
+		return MinRAMPercentage(0), fmt.Errorf("min RAM percentage does not match pattern '%s': %s", minRAMPercentageRE.String(), t)
+	}
+
+	groups := minRAMPercentageRE.FindStringSubmatch(t)
+	percentage, err := strconv.ParseFloat(groups[1], 64)
+	if err != nil {
+		return MinRAMPercentage(0), fmt.Errorf("min RAM percentage is not a number: %s", groups[1])
+	}
+
+	return MinRAMPercentage(percentage), nil
+}
+
+func (m MinRAMPercentage) String() string {
+	return fmt.Sprintf("-XX:MinRAMPercentage=%.2f", float64(m))
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var newRatioRE = regexp.MustCompile("^-XX:NewRatio=([\\d]+)$")
+
+// NewRatio is the ratio of the old generation to the young generation.
+type NewRatio int
+
+func IsNewRatio(s string) bool {
+	return newRatioRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseNewRatio(s string) (NewRatio, error) {
+	t := strings.TrimSpace(s)
+
+	if !newRatioRE.MatchString(t) {
+		return NewRatio(0), fmt.Errorf("new ratio does not match pattern '%s': %s", newRatioRE.String(), t)
+	}
+
+	groups := newRatioRE.FindStringSubmatch(t)
+	ratio, err := strconv.ParseInt(groups[1], 10, 64)
+	if err != nil {
+		return NewRatio(0), fmt.Errorf("new ratio is not an integer: %s", groups[1])
+	}
+
+	return NewRatio(ratio), nil
+}
+
+func (n NewRatio) String() string {
+	return fmt.Sprintf("-XX:NewRatio=%d", n)
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var newSizeRE = regexp.MustCompile(fmt.Sprintf("^-XX:NewSize=(%s)$", sizePattern))
+
+type NewSize Size
+
+func IsNewSize(s string) bool {
+	return newSizeRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseNewSize(s string) (NewSize, error) {
+	t := strings.TrimSpace(s)
+
+	if !newSizeRE.MatchString(t) {
+		return NewSize(0), fmt.Errorf("new size does not match pattern '%s': %s", newSizeRE.String(), t)
+	}
+
+	groups := newSizeRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return NewSize(0), err
+	}
+
+	return NewSize(size), nil
+}
+
+func (n NewSize) String() string {
+	return fmt.Sprintf("-XX:NewSize=%s", Size(n))
+}
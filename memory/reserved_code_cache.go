@@ -24,7 +24,7 @@ import (
 
 const DefaultReservedCodeCache = ReservedCodeCache(240 * Mibi)
 
-var reservedCodeCacheRE = regexp.MustCompile(fmt.Sprintf("^-XX:ReservedCodeCacheSize=(%s)$", sizePattern))
+var reservedCodeCacheRE = regexp.MustCompile(fmt.Sprintf("^(?:-XX:ReservedCodeCacheSize=|-Xmaxjitcodesize)(%s)$", sizePattern))
 
 type ReservedCodeCache Size
 
@@ -45,5 +45,13 @@ func TestReservedCodeCache(t *testing.T) {
 			g.Expect(memory.ParseReservedCodeCache("-XX:ReservedCodeCacheSize=1K")).To(Equal(memory.ReservedCodeCache(memory.Kibi)))
 		})
 
+		it("matches -Xmaxjitcodesize", func() {
+			g.Expect(memory.IsReservedCodeCache("-Xmaxjitcodesize1K")).To(BeTrue())
+		})
+
+		it("parses -Xmaxjitcodesize", func() {
+			g.Expect(memory.ParseReservedCodeCache("-Xmaxjitcodesize1K")).To(Equal(memory.ReservedCodeCache(memory.Kibi)))
+		})
+
 	})
 }
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var sharedClassCacheRE = regexp.MustCompile(fmt.Sprintf("^-Xscmx(%s)$", sizePattern))
+
+// SharedClassCache is the size of the OpenJ9 shared class cache, which is mapped into memory when -Xshareclasses is
+// enabled.
+type SharedClassCache Size
+
+func IsSharedClassCache(s string) bool {
+	return sharedClassCacheRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseSharedClassCache(s string) (SharedClassCache, error) {
+	t := strings.TrimSpace(s)
+
+	if !sharedClassCacheRE.MatchString(t) {
+		return SharedClassCache(0), fmt.Errorf("shared class cache does not match pattern '%s': %s", sharedClassCacheRE.String(), t)
+	}
+
+	groups := sharedClassCacheRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return SharedClassCache(0), err
+	}
+
+	return SharedClassCache(size), nil
+}
+
+func (s SharedClassCache) String() string {
+	return fmt.Sprintf("-Xscmx%s", Size(s))
+}
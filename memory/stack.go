@@ -24,19 +24,35 @@ import (
 
 const DefaultStack = Stack(Mibi)
 
-var stackRE = regexp.MustCompile(fmt.Sprintf("^-Xss(%s)$", sizePattern))
+var (
+	stackRE = regexp.MustCompile(fmt.Sprintf("^-Xss(%s)$", sizePattern))
+
+	// threadStackSizeRE matches -XX:ThreadStackSize whose value, including any size classification, is in kibibytes.
+	threadStackSizeRE = regexp.MustCompile(fmt.Sprintf("^-XX:ThreadStackSize=(%s)$", sizePattern))
+)
 
 type Stack Size
 
 func IsStack(s string) bool {
-	return stackRE.MatchString(strings.TrimSpace(s))
+	t := strings.TrimSpace(s)
+	return stackRE.MatchString(t) || threadStackSizeRE.MatchString(t)
 }
 
 func ParseStack(s string) (Stack, error) {
 	t := strings.TrimSpace(s)
 
+	if threadStackSizeRE.MatchString(t) {
+		groups := threadStackSizeRE.FindStringSubmatch(t)
+		size, err := ParseSize(groups[1])
+		if err != nil {
+			return Stack(0), err
+		}
+
+		return Stack(size * Kibi), nil
+	}
+
 	if !stackRE.MatchString(t) {
-		return Stack(0), fmt.Errorf("stack size does not match pattern '%s': %s", stackRE.String(), t)
+		return Stack(0), fmt.Errorf("stack size does not match pattern '%s' or '%s': %s", stackRE.String(), threadStackSizeRE.String(), t)
 	}
 
 	groups := stackRE.FindStringSubmatch(t)
@@ -45,5 +45,14 @@ func TestStack(t *testing.T) {
 			g.Expect(memory.ParseStack("-Xss1K")).To(Equal(memory.Stack(memory.Kibi)))
 		})
 
+		it("matches -XX:ThreadStackSize", func() {
+			g.Expect(memory.IsStack("-XX:ThreadStackSize=512")).To(BeTrue())
+		})
+
+		it("parses -XX:ThreadStackSize in kibibytes", func() {
+			g.Expect(memory.ParseStack("-XX:ThreadStackSize=512")).To(Equal(memory.Stack(512 * memory.Kibi)))
+			g.Expect(memory.ParseStack("-XX:ThreadStackSize=1k")).To(Equal(memory.Stack(memory.Mibi)))
+		})
+
 	})
 }
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tieredCompilationRE = regexp.MustCompile("^-XX:([+-])TieredCompilation$")
+
+// TieredCompilation is whether the JIT compiles in tiers, which determines the default reserved code cache.
+type TieredCompilation bool
+
+func IsTieredCompilation(s string) bool {
+	return tieredCompilationRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseTieredCompilation(s string) (TieredCompilation, error) {
+	t := strings.TrimSpace(s)
+
+	if !tieredCompilationRE.MatchString(t) {
+		return TieredCompilation(false), fmt.Errorf("tiered compilation does not match pattern '%s': %s", tieredCompilationRE.String(), t)
+	}
+
+	groups := tieredCompilationRE.FindStringSubmatch(t)
+	return TieredCompilation(groups[1] == "+"), nil
+}
+
+func (t TieredCompilation) String() string {
+	if t {
+		return "-XX:+TieredCompilation"
+	}
+
+	return "-XX:-TieredCompilation"
+}
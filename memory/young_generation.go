@@ -0,0 +1,51 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var youngGenerationRE = regexp.MustCompile(fmt.Sprintf("^-Xmn(%s)$", sizePattern))
+
+type YoungGeneration Size
+
+func IsYoungGeneration(s string) bool {
+	return youngGenerationRE.MatchString(strings.TrimSpace(s))
+}
+
+func ParseYoungGeneration(s string) (YoungGeneration, error) {
+	t := strings.TrimSpace(s)
+
+	if !youngGenerationRE.MatchString(t) {
+		return YoungGeneration(0), fmt.Errorf("young generation does not match pattern '%s': %s", youngGenerationRE.String(), t)
+	}
+
+	groups := youngGenerationRE.FindStringSubmatch(t)
+	size, err := ParseSize(groups[1])
+	if err != nil {
+		return YoungGeneration(0), err
+	}
+
+	return YoungGeneration(size), nil
+}
+
+func (y YoungGeneration) String() string {
+	return fmt.Sprintf("-Xmn%s", Size(y))
+}
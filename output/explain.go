@@ -0,0 +1,160 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/jvm"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+// Explain writes a table describing each step of a calculation: the size of every region, where it came from and
+// how it was derived.  The heap is omitted if the calculation failed before it was considered.
+func Explain(w io.Writer, c calculator.Calculator, r calculator.Result) error {
+	t := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	row := func(step string, source calculator.Source, derivation string, size memory.Size) {
+		_, _ = fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", step, source, derivation, size)
+	}
+
+	derive := func(region calculator.Region, flag fmt.Stringer, derivation string) string {
+		if r.IsFixed(region) {
+			return flag.String()
+		}
+
+		if r.Sources[region] == calculator.SourceReduced {
+			return fmt.Sprintf("reduced to fit %s minimum heap", memory.Size(*c.MinHeap))
+		}
+
+		return derivation
+	}
+
+	_, _ = fmt.Fprintln(t, "STEP\tSOURCE\tDERIVATION\tSIZE")
+
+	row("Total memory", "input", "--total-memory", r.TotalMemory)
+
+	headRoom := fmt.Sprintf("%s total memory x %g%%", r.TotalMemory, *c.HeadRoom)
+	if c.HeadRoomSize != nil && *c.HeadRoomSize != 0 {
+		headRoom = fmt.Sprintf("max(%s, %s)", headRoom, c.HeadRoomSize)
+	}
+
+	row("Head room", calculator.SourceCalculated, headRoom, r.HeadRoom)
+
+	row("Direct memory", r.Sources[calculator.RegionMaxDirectMemory],
+		derive(calculator.RegionMaxDirectMemory, r.MaxDirectMemory, "no reasonable heuristic"), memory.Size(r.MaxDirectMemory))
+
+	classes := fmt.Sprintf("(%dB x %d loaded classes) + %dB", calculator.MetaspacePerClass, *c.LoadedClassCount, calculator.MetaspaceBase)
+
+	overhead := []string{"head room", "direct memory"}
+
+	if r.IsPresent(calculator.RegionClassMemory) {
+		row("Class memory", r.Sources[calculator.RegionClassMemory], classes, r.ClassMemory)
+		overhead = append(overhead, "class memory")
+	} else if r.IsPresent(calculator.RegionMaxPermSize) {
+		row("Permanent generation", r.Sources[calculator.RegionMaxPermSize],
+			derive(calculator.RegionMaxPermSize, r.MaxPermSize, classes), memory.Size(r.MaxPermSize))
+		overhead = append(overhead, "permanent generation")
+	} else {
+		metaspace := classes
+		if c.JvmOptions != nil && c.JvmOptions.CompressedClassSpace != nil && !r.IsPresent(calculator.RegionCompressedClassSpace) {
+			metaspace = fmt.Sprintf("max(%s, %s)", classes, *c.JvmOptions.CompressedClassSpace)
+		}
+
+		row("Metaspace", r.Sources[calculator.RegionMaxMetaspace],
+			derive(calculator.RegionMaxMetaspace, r.MaxMetaspace, metaspace), memory.Size(r.MaxMetaspace))
+		overhead = append(overhead, "metaspace")
+	}
+
+	if r.IsPresent(calculator.RegionCompressedClassSpace) {
+		row("Compressed class space", r.Sources[calculator.RegionCompressedClassSpace],
+			fmt.Sprintf("%s with -XX:-UseCompressedClassPointers", r.CompressedClassSpace), memory.Size(r.CompressedClassSpace))
+		overhead = append(overhead, "compressed class space")
+	}
+
+	if r.IsPresent(calculator.RegionSharedClassCache) {
+		row("Shared class cache", r.Sources[calculator.RegionSharedClassCache],
+			r.SharedClassCache.String(), memory.Size(r.SharedClassCache))
+		overhead = append(overhead, "shared class cache")
+	}
+
+	if r.IsPresent(calculator.RegionCodeCacheTotal) {
+		row("Code cache total", r.Sources[calculator.RegionCodeCacheTotal],
+			derive(calculator.RegionCodeCacheTotal, r.CodeCacheTotal, "JVM default"), memory.Size(r.CodeCacheTotal))
+		overhead = append(overhead, "code cache total")
+	} else {
+		row("Reserved code cache", r.Sources[calculator.RegionReservedCodeCache],
+			derive(calculator.RegionReservedCodeCache, r.ReservedCodeCache, "JVM default"), memory.Size(r.ReservedCodeCache))
+		overhead = append(overhead, "reserved code cache")
+	}
+
+	overhead = append(overhead, "total stack")
+
+	row("Thread stack", r.Sources[calculator.RegionStack],
+		derive(calculator.RegionStack, r.Stack, "JVM default"), memory.Size(r.Stack))
+
+	row("Total stack", calculator.SourceCalculated,
+		fmt.Sprintf("%s thread stack x %d threads", memory.Size(r.Stack), r.ThreadCount), r.TotalStack())
+
+	if r.IsPresent(calculator.RegionInternalStack) {
+		row("Internal stack", r.Sources[calculator.RegionInternalStack],
+			fmt.Sprintf("%s thread stack x %d internal threads with %d CPUs", calculator.InternalThreadStack,
+				r.InternalThreadCount, r.CPUCount), r.InternalStack())
+		overhead = append(overhead, "internal stack")
+	}
+
+	if r.IsPresent(calculator.RegionCompilerArena) {
+		row("Compiler arena", r.Sources[calculator.RegionCompilerArena],
+			fmt.Sprintf("%s x %d compiler threads with %d CPUs", calculator.CompilerArenaPerThread,
+				jvm.CompilerThreads(r.CPUCount), r.CPUCount), r.CompilerArena)
+		overhead = append(overhead, "compiler arena")
+	}
+
+	if r.IsPresent(calculator.RegionGCOverhead) {
+		g := *c.JvmOptions.GarbageCollector
+		row("Garbage collector", r.Sources[calculator.RegionGCOverhead],
+			fmt.Sprintf("%s heap x %g%% with %s", memory.Size(r.MaxHeap), calculator.GCOverhead(g)*100, g), r.GCOverhead)
+		overhead = append(overhead, "garbage collector")
+	}
+
+	row("Overhead", calculator.SourceCalculated, strings.Join(overhead, " + "), r.Overhead)
+
+	if r.IsPresent(calculator.RegionMaxHeap) {
+		row("Heap", r.Sources[calculator.RegionMaxHeap],
+			derive(calculator.RegionMaxHeap, r.MaxHeap, fmt.Sprintf("%s total memory - %s overhead", r.TotalMemory, r.Overhead)),
+			memory.Size(r.MaxHeap))
+
+		if r.IsPresent(calculator.RegionInitialHeap) {
+			derivation := r.InitialHeap.String()
+			if !r.IsFixed(calculator.RegionInitialHeap) {
+				derivation = fmt.Sprintf("%s heap x %d%%", memory.Size(r.MaxHeap), *c.InitialHeapPercentage)
+			}
+
+			row("Initial heap", r.Sources[calculator.RegionInitialHeap], derivation, memory.Size(r.InitialHeap))
+		}
+
+		row("Unallocated", calculator.SourceCalculated,
+			fmt.Sprintf("%s total memory - %s overhead - %s heap", r.TotalMemory, r.Overhead, memory.Size(r.MaxHeap)),
+			r.Unallocated())
+	}
+
+	return t.Flush()
+}
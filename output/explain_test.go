@@ -0,0 +1,172 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/output"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestExplain(t *testing.T) {
+	spec.Run(t, "Explain", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var c calculator.Calculator
+
+		it.Before(func() {
+			h := flags.HeadRoom(0)
+			j := flags.JVMOptions{}
+			l := flags.LoadedClassCount(1000)
+			t := flags.ThreadCount(10)
+			m := flags.TotalMemory(memory.Gibi)
+
+			c = calculator.Calculator{HeadRoom: &h, JvmOptions: &j, LoadedClassCount: &l, ThreadCount: &t, TotalMemory: &m}
+		})
+
+		it("explains each step", func() {
+			s := memory.Stack(256 * memory.Kibi)
+			c.JvmOptions.Stack = &s
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, r)).To(Succeed())
+			g.Expect(b.String()).To(Equal(
+				`STEP                 SOURCE       DERIVATION                                                                 SIZE
+Total memory         input        --total-memory                                                             1G
+Head room            calculated   1G total memory x 0%                                                       0
+Direct memory        default      no reasonable heuristic                                                    10M
+Metaspace            calculated   (5800B x 1000 loaded classes) + 14000000B                                  19335K
+Reserved code cache  default      JVM default                                                                240M
+Thread stack         jvm-options  -Xss256K                                                                   256K
+Total stack          calculated   256K thread stack x 10 threads                                             2560K
+Overhead             calculated   head room + direct memory + metaspace + reserved code cache + total stack  277895K
+Heap                 calculated   1G total memory - 277895K overhead                                         770680K
+Unallocated          calculated   1G total memory - 277895K overhead - 770680K heap                          0
+`))
+		})
+
+		it("explains absolute head room", func() {
+			s := flags.HeadRoomSize(64 * memory.Mibi)
+			*c.HeadRoom, c.HeadRoomSize = 2.5, &s
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, r)).To(Succeed())
+			g.Expect(b.String()).To(MatchRegexp(`Head room\s+calculated\s+max\(1G total memory x 2.5%, 64M\)\s+64M`))
+		})
+
+		it("explains permanent generation for Java 7", func() {
+			v := flags.JVMVersion(7)
+			c.JvmVersion = &v
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, r)).To(Succeed())
+			g.Expect(b.String()).To(ContainSubstring("Permanent generation"))
+			g.Expect(b.String()).To(ContainSubstring("head room + direct memory + permanent generation + reserved code cache"))
+			g.Expect(b.String()).NotTo(ContainSubstring("Metaspace"))
+		})
+
+		it("explains OpenJ9 regions", func() {
+			v := flags.JVMVendor("openj9")
+			c.JvmVendor = &v
+			s := memory.SharedClassCache(64 * memory.Mibi)
+			c.JvmOptions.SharedClassCache = &s
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, r)).To(Succeed())
+			g.Expect(b.String()).To(ContainSubstring("Class memory"))
+			g.Expect(b.String()).To(ContainSubstring("Shared class cache"))
+			g.Expect(b.String()).To(ContainSubstring("Code cache total"))
+			g.Expect(b.String()).To(ContainSubstring("head room + direct memory + class memory + shared class cache + code cache total + total stack"))
+			g.Expect(b.String()).NotTo(ContainSubstring("Metaspace"))
+			g.Expect(b.String()).NotTo(ContainSubstring("Reserved code cache"))
+		})
+
+		it("explains garbage collector overhead", func() {
+			gc := memory.GarbageCollectorG1
+			c.JvmOptions.GarbageCollector = &gc
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, r)).To(Succeed())
+			g.Expect(b.String()).To(MatchRegexp(`Garbage collector\s+calculated\s+\S+ heap x 10% with -XX:\+UseG1GC`))
+			g.Expect(b.String()).To(ContainSubstring("reserved code cache + total stack + garbage collector"))
+		})
+
+		it("explains internal threads", func() {
+			cpus := flags.CPUCount(2)
+			c.CPUCount = &cpus
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, r)).To(Succeed())
+			g.Expect(b.String()).To(MatchRegexp(`Internal stack\s+calculated\s+1M thread stack x 14 internal threads with 2 CPUs\s+14M`))
+			g.Expect(b.String()).To(MatchRegexp(`Compiler arena\s+calculated\s+16M x 2 compiler threads with 2 CPUs\s+32M`))
+			g.Expect(b.String()).To(ContainSubstring("total stack + internal stack + compiler arena"))
+		})
+
+		it("explains reduced regions", func() {
+			mh := flags.MinHeap(900 * memory.Mibi)
+			c.MinHeap = &mh
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, r)).To(Succeed())
+			g.Expect(b.String()).To(MatchRegexp(`Reserved code cache\s+reduced\s+reduced to fit 900M minimum heap\s+\S+`))
+		})
+
+		it("omits heap if calculation failed before it was considered", func() {
+			m := flags.TotalMemory(100 * memory.Mibi)
+			c.TotalMemory = &m
+
+			_, err := c.CalculateResult()
+
+			var e *calculator.InsufficientMemoryError
+			g.Expect(errors.As(err, &e)).To(BeTrue())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Explain(b, c, e.Result)).To(Succeed())
+			g.Expect(b.String()).To(ContainSubstring("Overhead"))
+			g.Expect(b.String()).NotTo(ContainSubstring("Heap"))
+		})
+	})
+}
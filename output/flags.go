@@ -0,0 +1,169 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/environment"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+)
+
+// Options returns the calculated JVM options, excluding any that were specified by the user.
+func Options(r calculator.Result) []string {
+	f := r.Flags()
+	s := make([]string, len(f))
+
+	for i, o := range f {
+		s[i] = o.String()
+	}
+
+	return s
+}
+
+// MergedOptions returns the calculated JVM options followed by the effective options specified by the user, verbatim,
+// so that together they are the complete JVM configuration.  If the user's options were not parsed from tokens, their
+// string representation is used instead.
+func MergedOptions(r calculator.Result, j *flags.JVMOptions) []string {
+	s := Options(r)
+
+	if j == nil {
+		return s
+	}
+
+	if len(j.Tokens) == 0 {
+		if o := j.String(); o != "" {
+			return append(s, strings.Fields(o)...)
+		}
+
+		return s
+	}
+
+	return append(s, j.EffectiveTokens()...)
+}
+
+// Flags writes JVM options separated by spaces.
+func Flags(w io.Writer, options []string) error {
+	_, err := fmt.Fprintln(w, join(options))
+	return err
+}
+
+// ArgFile writes JVM options one per line, in the format of a java @argfile.
+func ArgFile(w io.Writer, options []string) error {
+	for _, o := range options {
+		if _, err := fmt.Fprintln(w, argFileQuote(o)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Env writes a JAVA_TOOL_OPTIONS=... line, in the format of an environment file, whose value is the existing options
+// followed by JVM options.
+func Env(w io.Writer, options []string, existing string) error {
+	_, err := fmt.Fprintf(w, "%s=%s\n", environment.JavaToolOptions, javaToolOptions(options, existing))
+	return err
+}
+
+// Shell writes an export JAVA_TOOL_OPTIONS=... statement, quoted for POSIX shells, whose value is the existing options
+// followed by JVM options.
+func Shell(w io.Writer, options []string, existing string) error {
+	_, err := fmt.Fprintf(w, "export %s=%s\n", environment.JavaToolOptions, shellQuote(javaToolOptions(options, existing)))
+	return err
+}
+
+// TOML writes a JAVA_TOOL_OPTIONS = "..." key, in the format of a Cloud Native Buildpacks exec.d environment override,
+// whose value is the existing options followed by JVM options.
+func TOML(w io.Writer, options []string, existing string) error {
+	_, err := fmt.Fprintf(w, "%s = %s\n", environment.JavaToolOptions, tomlQuote(javaToolOptions(options, existing)))
+	return err
+}
+
+func javaToolOptions(options []string, existing string) string {
+	s, e := join(options), strings.TrimSpace(existing)
+
+	switch {
+	case e == "":
+		return s
+	case s == "":
+		return e
+	default:
+		return e + " " + s
+	}
+}
+
+// join separates options by spaces, quoting options that contain whitespace or quotes so that they can be split again
+// by the JVM.  As the JVM does not process backslashes, single quotes are written as a double-quoted segment adjacent
+// to the single-quoted segments around them.
+func join(options []string) string {
+	s := make([]string, len(options))
+
+	for i, o := range options {
+		if strings.ContainsAny(o, " \t\r\n\"'") {
+			s[i] = "'" + strings.ReplaceAll(o, "'", `'"'"'`) + "'"
+		} else {
+			s[i] = o
+		}
+	}
+
+	return strings.Join(s, " ")
+}
+
+// argFileQuote quotes an option that contains whitespace, quotes or comments, escaping backslashes and double quotes
+// as the java launcher expects.
+func argFileQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\r\n\"'#\\") {
+		return s
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// tomlQuote quotes a TOML basic string, escaping backslashes, double quotes and control characters.
+func tomlQuote(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			_, _ = fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/environment"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/output"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/spf13/pflag"
+)
+
+func TestFlags(t *testing.T) {
+	spec.Run(t, "Flags", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var r calculator.Result
+
+		it.Before(func() {
+			r = calculator.Result{
+				MaxHeap: memory.MaxHeap(memory.Mibi),
+				Stack:   memory.Stack(memory.Kibi),
+				Sources: map[calculator.Region]calculator.Source{
+					calculator.RegionMaxDirectMemory:   calculator.SourceJVMOptions,
+					calculator.RegionMaxMetaspace:      calculator.SourceJVMOptions,
+					calculator.RegionReservedCodeCache: calculator.SourceJVMOptions,
+				},
+			}
+		})
+
+		it("merges options specified by the user", func() {
+			var j flags.JVMOptions
+			g.Expect(j.Set(`-XX:MaxDirectMemorySize=2M -Dgreeting="hello world" -XX:MaxMetaspaceSize=1M -XX:ReservedCodeCacheSize=1M -XX:MaxDirectMemorySize=1M`)).To(Succeed())
+
+			g.Expect(output.MergedOptions(r, &j)).To(Equal([]string{"-Xss1K", "-Xmx1M",
+				"-Dgreeting=hello world", "-XX:MaxMetaspaceSize=1M", "-XX:ReservedCodeCacheSize=1M", "-XX:MaxDirectMemorySize=1M"}))
+		})
+
+		it("merges JAVA_TOOL_OPTIONS with command line options", func() {
+			var j flags.JVMOptions
+			f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			f.Var(&j, flags.FlagJVMOptions, "")
+			g.Expect(f.Parse([]string{"--jvm-options", "-Xss512k"})).To(Succeed())
+
+			lookup := func(key string) (string, bool) {
+				if key == environment.JavaToolOptions {
+					return "-javaagent:/a.jar", true
+				}
+
+				return "", false
+			}
+			g.Expect(environment.Apply(f, lookup)).To(Succeed())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.Shell(b, output.MergedOptions(r, &j), "")).To(Succeed())
+			g.Expect(b.String()).To(Equal("export JAVA_TOOL_OPTIONS='-Xss1K -Xmx1M -javaagent:/a.jar -Xss512k'\n"))
+		})
+
+		it("merges options specified without tokens", func() {
+			d := memory.MaxDirectMemory(memory.Mibi)
+
+			g.Expect(output.MergedOptions(r, &flags.JVMOptions{MaxDirectMemory: &d})).To(Equal([]string{"-Xss1K", "-Xmx1M", "-XX:MaxDirectMemorySize=1M"}))
+		})
+
+		it("does not escape backslashes", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.Flags(b, []string{`-Djava.io.tmpdir=C:\tmp`, `-Dgreeting=a\ b`})).To(Succeed())
+			g.Expect(b.String()).To(Equal(`-Djava.io.tmpdir=C:\tmp '-Dgreeting=a\ b'` + "\n"))
+		})
+
+		it("quotes options containing whitespace", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.Flags(b, []string{"-Xss1K", "-Dgreeting=hello world", `-Dquote=it's "x"`})).To(Succeed())
+			g.Expect(b.String()).To(Equal(`-Xss1K '-Dgreeting=hello world' '-Dquote=it'"'"'s "x"'` + "\n"))
+
+			b = &bytes.Buffer{}
+			g.Expect(output.ArgFile(b, []string{"-Xss1K", "-Dgreeting=hello world"})).To(Succeed())
+			g.Expect(b.String()).To(Equal("-Xss1K\n\"-Dgreeting=hello world\"\n"))
+		})
+
+		it("writes options separated by spaces", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.Flags(b, output.Options(r))).To(Succeed())
+			g.Expect(b.String()).To(Equal("-Xss1K -Xmx1M\n"))
+		})
+
+		it("writes options one per line", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.ArgFile(b, output.Options(r))).To(Succeed())
+			g.Expect(b.String()).To(Equal("-Xss1K\n-Xmx1M\n"))
+		})
+
+		it("writes JAVA_TOOL_OPTIONS environment file line", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.Env(b, output.Options(r), "")).To(Succeed())
+			g.Expect(b.String()).To(Equal("JAVA_TOOL_OPTIONS=-Xss1K -Xmx1M\n"))
+		})
+
+		it("appends options to existing JAVA_TOOL_OPTIONS", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.Env(b, output.Options(r), " -Dfoo=bar ")).To(Succeed())
+			g.Expect(b.String()).To(Equal("JAVA_TOOL_OPTIONS=-Dfoo=bar -Xss1K -Xmx1M\n"))
+		})
+
+		it("writes quoted shell export", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.Shell(b, output.Options(r), `-Dgreeting='hello world'`)).To(Succeed())
+			g.Expect(b.String()).To(Equal(`export JAVA_TOOL_OPTIONS='-Dgreeting='"'"'hello world'"'"' -Xss1K -Xmx1M'` + "\n"))
+		})
+
+		it("writes quoted TOML key", func() {
+			b := &bytes.Buffer{}
+			g.Expect(output.TOML(b, output.Options(r), `-Dgreeting="hello world" -Dpath=C:\tmp`)).To(Succeed())
+			g.Expect(b.String()).To(Equal(`JAVA_TOOL_OPTIONS = "-Dgreeting=\"hello world\" -Dpath=C:\\tmp -Xss1K -Xmx1M"` + "\n"))
+		})
+	})
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+)
+
+type jsonDocument struct {
+	Inputs      jsonInputs            `json:"inputs"`
+	Options     []string              `json:"options"`
+	Regions     map[string]jsonRegion `json:"regions"`
+	Overhead    int64                 `json:"overhead"`
+	Unallocated int64                 `json:"unallocated"`
+}
+
+type jsonInputs struct {
+	CPUCount         int     `json:"cpu_count,omitempty"`
+	HeadRoom         float64 `json:"head_room"`
+	HeadRoomSize     int64   `json:"head_room_size,omitempty"`
+	JVMOptions       string  `json:"jvm_options"`
+	JVMVendor        string  `json:"jvm_vendor"`
+	JVMVersion       int     `json:"jvm_version,omitempty"`
+	LoadedClassCount int     `json:"loaded_class_count"`
+	MinHeap          int64   `json:"min_heap,omitempty"`
+	ThreadCount      int     `json:"thread_count"`
+	TotalMemory      int64   `json:"total_memory"`
+}
+
+type jsonRegion struct {
+	Bytes  int64  `json:"bytes"`
+	Flag   string `json:"flag,omitempty"`
+	Fixed  bool   `json:"fixed"`
+	Source string `json:"source"`
+}
+
+// JSON writes the inputs, every region and the unallocated memory of a calculation, and the JVM options to print, as a
+// JSON document.  All sizes are expressed in bytes.
+func JSON(w io.Writer, c calculator.Calculator, r calculator.Result, options []string) error {
+	if options == nil {
+		options = []string{}
+	}
+
+	d := jsonDocument{
+		Options: options,
+		Inputs: jsonInputs{
+			CPUCount:         r.CPUCount,
+			HeadRoom:         float64(*c.HeadRoom),
+			JVMVendor:        string(r.JVMVendor),
+			JVMVersion:       r.JVMVersion,
+			LoadedClassCount: int(*c.LoadedClassCount),
+			ThreadCount:      r.ThreadCount,
+			TotalMemory:      int64(r.TotalMemory),
+		},
+		Regions: map[string]jsonRegion{
+			"head_room":   {Bytes: int64(r.HeadRoom), Source: string(calculator.SourceCalculated)},
+			"total_stack": {Bytes: int64(r.TotalStack()), Source: string(calculator.SourceCalculated)},
+		},
+		Overhead:    int64(r.Overhead),
+		Unallocated: int64(r.Unallocated()),
+	}
+
+	if c.HeadRoomSize != nil {
+		d.Inputs.HeadRoomSize = int64(*c.HeadRoomSize)
+	}
+
+	if c.MinHeap != nil {
+		d.Inputs.MinHeap = int64(*c.MinHeap)
+	}
+
+	if c.JvmOptions != nil {
+		d.Inputs.JVMOptions = c.JvmOptions.String()
+	}
+
+	if r.IsPresent(calculator.RegionClassMemory) {
+		d.Regions[string(calculator.RegionClassMemory)] = jsonRegion{Bytes: int64(r.ClassMemory), Source: string(calculator.SourceCalculated)}
+	}
+
+	if r.IsPresent(calculator.RegionCompilerArena) {
+		d.Regions[string(calculator.RegionCompilerArena)] = jsonRegion{Bytes: int64(r.CompilerArena), Source: string(calculator.SourceCalculated)}
+	}
+
+	if r.IsPresent(calculator.RegionInternalStack) {
+		d.Regions[string(calculator.RegionInternalStack)] = jsonRegion{Bytes: int64(r.InternalStack()), Source: string(calculator.SourceCalculated)}
+	}
+
+	if r.IsPresent(calculator.RegionGCOverhead) {
+		d.Regions[string(calculator.RegionGCOverhead)] = jsonRegion{Bytes: int64(r.GCOverhead), Source: string(calculator.SourceCalculated)}
+	}
+
+	region(d.Regions, r, calculator.RegionCodeCacheTotal, memory.Size(r.CodeCacheTotal), r.CodeCacheTotal)
+	region(d.Regions, r, calculator.RegionCompressedClassSpace, memory.Size(r.CompressedClassSpace), r.CompressedClassSpace)
+	var initialHeap, maxHeap fmt.Stringer = r.InitialHeap, r.MaxHeap
+	if r.InitialRAMPercentage > 0 {
+		initialHeap = r.InitialRAMPercentage
+	}
+	if r.MaxRAMPercentage > 0 {
+		maxHeap = r.MaxRAMPercentage
+	}
+
+	region(d.Regions, r, calculator.RegionInitialHeap, memory.Size(r.InitialHeap), initialHeap)
+	region(d.Regions, r, calculator.RegionMaxDirectMemory, memory.Size(r.MaxDirectMemory), r.MaxDirectMemory)
+	region(d.Regions, r, calculator.RegionMaxHeap, memory.Size(r.MaxHeap), maxHeap)
+	region(d.Regions, r, calculator.RegionMaxMetaspace, memory.Size(r.MaxMetaspace), r.MaxMetaspace)
+	region(d.Regions, r, calculator.RegionMaxPermSize, memory.Size(r.MaxPermSize), r.MaxPermSize)
+	region(d.Regions, r, calculator.RegionReservedCodeCache, memory.Size(r.ReservedCodeCache), r.ReservedCodeCache)
+	region(d.Regions, r, calculator.RegionSharedClassCache, memory.Size(r.SharedClassCache), r.SharedClassCache)
+	region(d.Regions, r, calculator.RegionStack, memory.Size(r.Stack), r.Stack)
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(d)
+}
+
+// region adds a region to the document if it is present in the result.
+func region(regions map[string]jsonRegion, r calculator.Result, region calculator.Region, size memory.Size, flag fmt.Stringer) {
+	if !r.IsPresent(region) {
+		return
+	}
+
+	regions[string(region)] = jsonRegion{
+		Bytes:  int64(size),
+		Flag:   flag.String(),
+		Fixed:  r.IsFixed(region),
+		Source: string(r.Sources[region]),
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2015-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/calculator"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/flags"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/memory"
+	"github.com/cloudfoundry/java-buildpack-memory-calculator/v4/output"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+)
+
+func TestJSON(t *testing.T) {
+	spec.Run(t, "JSON", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := NewGomegaWithT(t)
+
+		var c calculator.Calculator
+
+		it.Before(func() {
+			h := flags.HeadRoom(10)
+			j := flags.JVMOptions{}
+			l := flags.LoadedClassCount(1000)
+			t := flags.ThreadCount(10)
+			m := flags.TotalMemory(memory.Gibi)
+
+			c = calculator.Calculator{HeadRoom: &h, JvmOptions: &j, LoadedClassCount: &l, ThreadCount: &t, TotalMemory: &m}
+		})
+
+		it("writes inputs, regions and unallocated memory", func() {
+			h := memory.MaxHeap(512 * memory.Mibi)
+			c.JvmOptions.MaxHeap = &h
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.JSON(b, c, r, output.Options(r))).To(Succeed())
+
+			var d map[string]interface{}
+			g.Expect(json.Unmarshal(b.Bytes(), &d)).To(Succeed())
+
+			g.Expect(d["inputs"]).To(Equal(map[string]interface{}{
+				"head_room":          float64(10),
+				"jvm_options":        "-Xmx512M",
+				"jvm_vendor":         "hotspot",
+				"loaded_class_count": float64(1000),
+				"thread_count":       float64(10),
+				"total_memory":       float64(memory.Gibi),
+			}))
+
+			g.Expect(d["options"]).To(Equal([]interface{}{
+				"-XX:MaxDirectMemorySize=10M", "-XX:MaxMetaspaceSize=19335K", "-XX:ReservedCodeCacheSize=240M", "-Xss1M",
+			}))
+
+			regions := d["regions"].(map[string]interface{})
+			g.Expect(regions).To(HaveLen(7))
+			g.Expect(regions["head_room"]).To(HaveKeyWithValue("bytes", float64(107374182)))
+			g.Expect(regions["max_heap"]).To(Equal(map[string]interface{}{
+				"bytes": float64(512 * memory.Mibi), "flag": "-Xmx512M", "fixed": true, "source": "jvm-options",
+			}))
+			g.Expect(regions["max_metaspace"]).To(Equal(map[string]interface{}{
+				"bytes": float64(19800000), "flag": "-XX:MaxMetaspaceSize=19335K", "fixed": false, "source": "calculated",
+			}))
+			g.Expect(regions["stack"]).To(Equal(map[string]interface{}{
+				"bytes": float64(memory.Mibi), "flag": "-Xss1M", "fixed": false, "source": "default",
+			}))
+			g.Expect(regions["total_stack"]).To(HaveKeyWithValue("bytes", float64(10*memory.Mibi)))
+
+			g.Expect(d["overhead"]).To(Equal(float64(r.Overhead)))
+			g.Expect(d["unallocated"]).To(Equal(float64(memory.Gibi - r.Overhead - 512*memory.Mibi)))
+		})
+
+		it("writes heap flag as percentage", func() {
+			m := flags.HeapModePercentage
+			c.HeapMode = &m
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.JSON(b, c, r, output.Options(r))).To(Succeed())
+
+			var d map[string]interface{}
+			g.Expect(json.Unmarshal(b.Bytes(), &d)).To(Succeed())
+
+			regions := d["regions"].(map[string]interface{})
+			g.Expect(regions["max_heap"]).To(HaveKeyWithValue("flag", r.MaxRAMPercentage.String()))
+			g.Expect(regions["max_heap"]).To(HaveKeyWithValue("bytes", float64(r.MaxHeap)))
+		})
+
+		it("writes OpenJ9 regions", func() {
+			v := flags.JVMVendor("openj9")
+			c.JvmVendor = &v
+
+			r, err := c.CalculateResult()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			b := &bytes.Buffer{}
+			g.Expect(output.JSON(b, c, r, output.Options(r))).To(Succeed())
+
+			var d map[string]interface{}
+			g.Expect(json.Unmarshal(b.Bytes(), &d)).To(Succeed())
+
+			regions := d["regions"].(map[string]interface{})
+			g.Expect(regions).NotTo(HaveKey("max_metaspace"))
+			g.Expect(regions).NotTo(HaveKey("reserved_code_cache"))
+			g.Expect(regions["class_memory"]).To(Equal(map[string]interface{}{
+				"bytes": float64(19800000), "fixed": false, "source": "calculated",
+			}))
+			g.Expect(regions["code_cache_total"]).To(Equal(map[string]interface{}{
+				"bytes": float64(256 * memory.Mibi), "flag": "-Xcodecachetotal256M", "fixed": false, "source": "default",
+			}))
+		})
+	})
+}